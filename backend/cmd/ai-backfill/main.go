@@ -0,0 +1,149 @@
+// ai-backfill re-runs AI classification on existing tickets, e.g. after a
+// prompt or model change, or for tickets whose events were lost.
+//
+//	go run ./cmd/ai-backfill -status open -dry-run                       # list what would run
+//	go run ./cmd/ai-backfill -category Billing -from 2025-01-01 -rate 0.5 # reclassify
+//
+// Arguments after "--" are passed to the config loader (e.g. -- -ai.model gemini-2.5-pro).
+package main
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/db"
+	"ai-ticketing-backend/services/ai"
+	"ai-ticketing-backend/services/ai/llm"
+	"ai-ticketing-backend/services/ai/repository"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+func main() {
+	fs := flag.NewFlagSet("ai-backfill", flag.ExitOnError)
+	statuses := fs.String("status", strings.Join(ai.TriageStatuses, ","), "comma-separated ticket statuses to select")
+	category := fs.String("category", "", "only tickets in this category")
+	from := fs.String("from", "", "only tickets created on or after this date (YYYY-MM-DD)")
+	to := fs.String("to", "", "only tickets created before this date (YYYY-MM-DD)")
+	limit := fs.Int("limit", 0, "reclassify at most N tickets")
+	rate := fs.Float64("rate", 1, "tickets per second")
+	dryRun := fs.Bool("dry-run", false, "list the selected tickets without classifying them")
+	verbose := fs.Bool("v", false, "show AI service logs")
+	fs.Parse(os.Args[1:])
+
+	filter := repository.TicketFilter{Category: *category, Limit: *limit}
+	for _, status := range strings.Split(*statuses, ",") {
+		status = strings.TrimSpace(status)
+		if status == "" {
+			continue
+		}
+		if !slices.Contains(ai.TriageStatuses, status) {
+			log.Fatalf("Status %q can't be reclassified; use one of %s", status, strings.Join(ai.TriageStatuses, ", "))
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	var err error
+	if filter.From, err = parseDate(*from); err != nil {
+		log.Fatal("Invalid -from: ", err)
+	}
+	if filter.To, err = parseDate(*to); err != nil {
+		log.Fatal("Invalid -to: ", err)
+	}
+	if *rate <= 0 {
+		log.Fatal("-rate must be positive")
+	}
+
+	cfg, err := config.Load("ai-backfill", fs.Args())
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	dbConn, err := db.New(cfg.DB.DSN())
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	tickets, err := repository.NewTicketRepository(dbConn).FindTickets(filter)
+	if err != nil {
+		log.Fatal("Failed to select tickets:", err)
+	}
+
+	if *dryRun {
+		for _, t := range tickets {
+			fmt.Printf("%s  %-14s  %-12s  %s  %s\n", t.ID, t.Status, t.Category, t.CreatedAt.Format("2006-01-02"), t.Title)
+		}
+		fmt.Printf("\n%d tickets would be reclassified (about %s at %.2g/s)\n", len(tickets), estimate(len(tickets), *rate), *rate)
+		return
+	}
+	if len(tickets) == 0 {
+		fmt.Println("No tickets match")
+		return
+	}
+
+	// Same service the consumer uses, so results are applied and published the same way
+	svc := ai.Setup(cfg)
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+	defer ticker.Stop()
+
+	var done, failed, inputTokens, outputTokens int
+	started := time.Now()
+loop:
+	for i, t := range tickets {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				fmt.Println("Interrupted")
+				break loop
+			case <-ticker.C:
+			}
+		}
+		run, err := svc.Backfill(t.ID)
+		progress := fmt.Sprintf("[%d/%d]", i+1, len(tickets))
+		switch {
+		case errors.Is(err, llm.ErrUnavailable):
+			// Everything after this would end up queued too
+			fmt.Printf("%s %s: %v\nStopping: the AI provider is unavailable\n", progress, t.ID, err)
+			failed++
+			break loop
+		case err != nil:
+			fmt.Printf("%s %s: %v\n", progress, t.ID, err)
+			failed++
+			continue
+		}
+		done++
+		inputTokens += run.InputTokens
+		outputTokens += run.OutputTokens
+		fmt.Printf("%s %s: %s/%s -> %s/%s (%.2f, %s)\n", progress, t.ID, t.Category, t.Priority, run.Category, run.Priority, run.Confidence, run.Status)
+	}
+	log.SetOutput(os.Stderr)
+
+	fmt.Printf("\nReclassified %d, failed %d, skipped %d of %d tickets in %s\n",
+		done, failed, len(tickets)-done-failed, len(tickets), time.Since(started).Round(time.Second))
+	fmt.Printf("Tokens: %d in, %d out (~$%.4f)\n", inputTokens, outputTokens, cfg.AI.Cost(inputTokens, outputTokens))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// estimate is how long n tickets take at the given rate, ignoring LLM latency
+func estimate(n int, rate float64) time.Duration {
+	return time.Duration(float64(n) / rate * float64(time.Second)).Round(time.Second)
+}
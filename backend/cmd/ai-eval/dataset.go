@@ -0,0 +1,204 @@
+package main
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/repository"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// example is one labeled ticket in the dataset (one JSON object per line)
+type example struct {
+	ID          string           `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Tenant      string           `json:"tenant,omitempty"`
+	Comments    []exampleComment `json:"comments,omitempty"`
+	History     []exampleTicket  `json:"history,omitempty"` // Customer's earlier tickets
+	Category    string           `json:"category"`          // Expected labels
+	Priority    string           `json:"priority"`
+}
+
+type exampleComment struct {
+	Role   string `json:"role"`
+	Body   string `json:"body"`
+	Public *bool  `json:"public,omitempty"` // Defaults to true
+}
+
+type exampleTicket struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	Status      string `json:"status"`
+}
+
+func loadDataset(path string) ([]example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var examples []example
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var ex example
+		if err := json.Unmarshal(scanner.Bytes(), &ex); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if ex.ID == "" {
+			ex.ID = fmt.Sprintf("line-%d", line)
+		}
+		if ex.Category == "" || ex.Priority == "" {
+			return nil, fmt.Errorf("%s:%d: example %s has no expected category/priority", path, line, ex.ID)
+		}
+		examples = append(examples, ex)
+	}
+	return examples, scanner.Err()
+}
+
+// epoch stamps every generated record so prompts, and therefore replay
+// keys, are identical between runs
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// memRepo serves one example at a time to the real AIService in place of
+// Postgres, and captures what the service writes back.
+type memRepo struct {
+	ticket     *models.Ticket
+	comments   []models.Comment
+	history    []models.Ticket
+	categories []models.Category
+	priorities []models.Priority
+	prompts    []models.PromptTemplate
+	articles   []models.Article
+	run        *models.AIClassification
+}
+
+// load makes ex the ticket the service will see
+func (r *memRepo) load(ex example) uuid.UUID {
+	id := uuid.NewSHA1(uuid.NameSpaceURL, []byte("ai-eval:"+ex.ID))
+	r.ticket = &models.Ticket{
+		ID:          id,
+		Title:       ex.Title,
+		Description: ex.Description,
+		Status:      "open",
+		UserID:      uuid.NewSHA1(uuid.NameSpaceURL, []byte("ai-eval-user:"+ex.ID)),
+		User:        models.User{Tenant: ex.Tenant},
+		CreatedAt:   epoch,
+	}
+	r.comments = nil
+	for i, c := range ex.Comments {
+		public := c.Public == nil || *c.Public
+		r.comments = append(r.comments, models.Comment{
+			TicketID: id, AuthorRole: c.Role, Body: c.Body, Public: public,
+			CreatedAt: epoch.Add(time.Duration(i+1) * time.Minute),
+		})
+	}
+	r.history = nil
+	for i, h := range ex.History {
+		r.history = append(r.history, models.Ticket{
+			Title: h.Title, Description: h.Description, Category: h.Category, Status: h.Status,
+			CreatedAt: epoch.AddDate(0, 0, -(i + 1)),
+		})
+	}
+	r.run = nil
+	return id
+}
+
+func (r *memRepo) GetByID(id uuid.UUID) (*models.Ticket, error) {
+	t := *r.ticket
+	return &t, nil
+}
+
+func (r *memRepo) ApplyClassification(ticket *models.Ticket, fromStatus string, answer *models.Comment) (bool, error) {
+	return true, nil
+}
+
+func (r *memRepo) ListComments(ticketID uuid.UUID) ([]models.Comment, error) {
+	return r.comments, nil
+}
+
+func (r *memRepo) ListAttachments(ticketID uuid.UUID) ([]models.Attachment, error) {
+	return nil, nil
+}
+
+func (r *memRepo) ListRecentByUser(userID, excludeID uuid.UUID, limit int) ([]models.Ticket, error) {
+	if len(r.history) > limit {
+		return r.history[:limit], nil
+	}
+	return r.history, nil
+}
+
+func (r *memRepo) ListCategories() ([]models.Category, error) { return r.categories, nil }
+func (r *memRepo) ListPriorities() ([]models.Priority, error) { return r.priorities, nil }
+
+func (r *memRepo) CreateClassification(run *models.AIClassification) error {
+	r.run = run
+	return nil
+}
+
+// Every case is classified afresh
+func (r *memRepo) LatestClassification(ticketID uuid.UUID) (*models.AIClassification, error) {
+	return nil, nil
+}
+
+func (r *memRepo) ListActivePrompts(name string) ([]models.PromptTemplate, error) {
+	var prompts []models.PromptTemplate
+	for _, p := range r.prompts {
+		if p.Name == name {
+			prompts = append(prompts, p)
+		}
+	}
+	return prompts, nil
+}
+
+// Duplicate detection is disabled in evaluations
+func (r *memRepo) SaveEmbedding(e *models.TicketEmbedding) error { return nil }
+func (r *memRepo) ListEmbeddings(model string, since time.Time) ([]models.TicketEmbedding, error) {
+	return nil, nil
+}
+func (r *memRepo) ReplaceSimilar(ticketID uuid.UUID, similar []models.TicketSimilarity) error {
+	return nil
+}
+
+func (r *memRepo) ListPublishedArticles() ([]models.Article, error) { return r.articles, nil }
+
+// Summaries aren't part of classification and aren't evaluated
+func (r *memRepo) CreateDraft(draft *models.ReplyDraft) error { return nil }
+
+func (r *memRepo) GetSummary(ticketID uuid.UUID) (*models.TicketSummary, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *memRepo) SaveSummary(summary *models.TicketSummary) error { return nil }
+
+func (r *memRepo) ClaimTickets(from, to string, staleBefore time.Time, limit int) ([]models.Ticket, error) {
+	return nil, nil
+}
+func (r *memRepo) ReleaseTickets(ids []uuid.UUID, from, to string) error { return nil }
+func (r *memRepo) FindTickets(filter repository.TicketFilter) ([]models.Ticket, error) {
+	return nil, nil
+}
+
+func (r *memRepo) ListTicketsByID(ids []uuid.UUID) ([]models.Ticket, error) { return nil, nil }
+
+// The copilot isn't evaluated
+func (r *memRepo) CreateConversation(conversation *models.CopilotConversation) error { return nil }
+func (r *memRepo) GetConversation(id uuid.UUID) (*models.CopilotConversation, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+func (r *memRepo) AddCopilotMessage(message *models.CopilotMessage) error { return nil }
+
+// Evaluations run without budgets
+func (r *memRepo) GetUsage(tenant, day string) (*models.AIUsage, error) { return nil, nil }
+func (r *memRepo) AddUsage(usage *models.AIUsage) error                 { return nil }
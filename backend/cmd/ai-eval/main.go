@@ -0,0 +1,166 @@
+// ai-eval runs a labeled dataset through the AI service's classification
+// path offline and reports accuracy, per-class metrics, latency and cost.
+//
+//	go run ./cmd/ai-eval -dataset tickets.jsonl -record rec.jsonl          # live provider, recorded
+//	go run ./cmd/ai-eval -dataset tickets.jsonl -replay rec.jsonl \
+//	    -prompt candidate.tmpl -baseline eval-previous.json                # replayed, compared
+//
+// Arguments after "--" are passed to the config loader (e.g. -- -ai.model gemini-2.5-pro).
+package main
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/prompt"
+	"ai-ticketing-backend/services/ai"
+	"ai-ticketing-backend/services/ai/llm"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	fs := flag.NewFlagSet("ai-eval", flag.ExitOnError)
+	datasetPath := fs.String("dataset", "", "labeled JSONL dataset (required)")
+	outPath := fs.String("out", "", "where to write results (default eval-<time>.json)")
+	baselinePath := fs.String("baseline", "", "previous results file to diff against")
+	promptPath := fs.String("prompt", "", "classify prompt template to evaluate instead of the embedded one")
+	promptVersion := fs.Int("prompt-version", 0, "version number to label -prompt results with")
+	taxonomyPath := fs.String("taxonomy", "", `JSON {"categories": [...], "priorities": [...]} (default: built-in taxonomy)`)
+	recordPath := fs.String("record", "", "append every provider response to this replay file")
+	replayPath := fs.String("replay", "", "answer from a replay file instead of calling the provider")
+	limit := fs.Int("limit", 0, "only evaluate the first N examples")
+	verbose := fs.Bool("v", false, "show AI service logs")
+	fs.Parse(os.Args[1:])
+
+	if *datasetPath == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *replayPath != "" {
+		os.Setenv("AI_PROVIDER", "replay")
+		os.Setenv("AI_REPLAY_FILE", *replayPath)
+	}
+	cfg, err := config.Load("ai-eval", fs.Args())
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	examples, err := loadDataset(*datasetPath)
+	if err != nil {
+		log.Fatal("Failed to load dataset:", err)
+	}
+	if *limit > 0 && *limit < len(examples) {
+		examples = examples[:*limit]
+	}
+
+	repo := &memRepo{}
+	if *taxonomyPath != "" {
+		if err := loadTaxonomy(*taxonomyPath, repo); err != nil {
+			log.Fatal("Failed to load taxonomy:", err)
+		}
+	}
+	if *promptPath != "" {
+		body, err := os.ReadFile(*promptPath)
+		if err != nil {
+			log.Fatal("Failed to read prompt:", err)
+		}
+		if err := prompt.Validate("classify", string(body)); err != nil {
+			log.Fatal("Invalid prompt: ", err)
+		}
+		repo.prompts = []models.PromptTemplate{{Name: "classify", Version: *promptVersion, Body: string(body), Status: models.PromptActive}}
+	}
+
+	provider, err := llm.New(cfg.AI)
+	if err != nil {
+		log.Fatal("Failed to create provider:", err)
+	}
+	if *recordPath != "" {
+		if provider, err = llm.NewRecorder(provider, *recordPath); err != nil {
+			log.Fatal("Failed to open record file:", err)
+		}
+	}
+	// Same service the consumer uses; storage, the DB, duplicate detection and events are left out
+	svc := ai.NewAIService(repo, nil, provider, nil, nil, nil, cfg.AI)
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+	run := &evalRun{
+		StartedAt: time.Now().UTC(),
+		Dataset:   *datasetPath,
+		Provider:  provider.Name(),
+		Model:     provider.Model(),
+	}
+	for i, ex := range examples {
+		id := repo.load(ex)
+		started := time.Now()
+		err := svc.ProcessTicketContent(id, ex.Title, ex.Description)
+		res := result{ID: ex.ID, ExpectedCategory: ex.Category, ExpectedPriority: ex.Priority, LatencyMS: time.Since(started).Milliseconds()}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		if r := repo.run; r != nil {
+			res.Category, res.Priority, res.Confidence, res.Valid = r.Category, r.Priority, r.Confidence, r.Valid
+			res.InputTokens, res.OutputTokens = r.InputTokens, r.OutputTokens
+			res.CostUSD = cfg.AI.Cost(r.InputTokens, r.OutputTokens)
+			res.LatencyMS = r.LatencyMS // Provider time only, without context building
+			run.Model, run.PromptVersion = r.Model, r.PromptVersion
+		}
+		run.Results = append(run.Results, res)
+		fmt.Fprintf(os.Stderr, "\r%d/%d", i+1, len(examples))
+	}
+	fmt.Fprintln(os.Stderr)
+	log.SetOutput(os.Stderr)
+	run.Summary = summarize(run.Results)
+
+	if *outPath == "" {
+		*outPath = "eval-" + run.StartedAt.Format("20060102-150405") + ".json"
+	}
+	data, _ := json.MarshalIndent(run, "", "  ")
+	if err := os.WriteFile(*outPath, data, 0o644); err != nil {
+		log.Fatal("Failed to write results:", err)
+	}
+
+	printSummary(os.Stdout, run)
+	if *baselinePath != "" {
+		baseline, err := loadRun(*baselinePath)
+		if err != nil {
+			log.Fatal("Failed to load baseline:", err)
+		}
+		printDiff(os.Stdout, baseline, run)
+	}
+	fmt.Printf("\nResults written to %s\n", *outPath)
+}
+
+func loadTaxonomy(path string, repo *memRepo) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var t struct {
+		Categories []models.Category `json:"categories"`
+		Priorities []models.Priority `json:"priorities"`
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	repo.categories, repo.priorities = t.Categories, t.Priorities
+	return nil
+}
+
+func loadRun(path string) (*evalRun, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var run evalRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
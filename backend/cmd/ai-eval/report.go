@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// evalRun is the results file; a previous one can be passed as -baseline
+type evalRun struct {
+	StartedAt     time.Time `json:"started_at"`
+	Dataset       string    `json:"dataset"`
+	Provider      string    `json:"provider"`
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"prompt_version"`
+	Summary       summary   `json:"summary"`
+	Results       []result  `json:"results"`
+}
+
+type result struct {
+	ID               string  `json:"id"`
+	ExpectedCategory string  `json:"expected_category"`
+	ExpectedPriority string  `json:"expected_priority"`
+	Category         string  `json:"category"`
+	Priority         string  `json:"priority"`
+	Confidence       float64 `json:"confidence"`
+	Valid            bool    `json:"valid"`
+	LatencyMS        int64   `json:"latency_ms"`
+	InputTokens      int     `json:"input_tokens"`
+	OutputTokens     int     `json:"output_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	Error            string  `json:"error,omitempty"`
+}
+
+func (r result) categoryCorrect() bool { return r.Error == "" && r.Category == r.ExpectedCategory }
+func (r result) priorityCorrect() bool { return r.Error == "" && r.Priority == r.ExpectedPriority }
+
+type summary struct {
+	Examples         int           `json:"examples"`
+	Errors           int           `json:"errors"`
+	Invalid          int           `json:"invalid"` // Output failed validation and defaults were used
+	CategoryAccuracy float64       `json:"category_accuracy"`
+	PriorityAccuracy float64       `json:"priority_accuracy"`
+	Categories       []classMetric `json:"categories"`
+	Priorities       []classMetric `json:"priorities"`
+	LatencyP50MS     int64         `json:"latency_p50_ms"`
+	LatencyP95MS     int64         `json:"latency_p95_ms"`
+	InputTokens      int           `json:"input_tokens"`
+	OutputTokens     int           `json:"output_tokens"`
+	CostUSD          float64       `json:"cost_usd"`
+}
+
+type classMetric struct {
+	Name      string  `json:"name"`
+	Support   int     `json:"support"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+}
+
+func summarize(results []result) summary {
+	s := summary{Examples: len(results)}
+	var latencies []int64
+	var categoryHits, priorityHits int
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			s.Errors++
+		case !r.Valid:
+			s.Invalid++
+		}
+		if r.categoryCorrect() {
+			categoryHits++
+		}
+		if r.priorityCorrect() {
+			priorityHits++
+		}
+		latencies = append(latencies, r.LatencyMS)
+		s.InputTokens += r.InputTokens
+		s.OutputTokens += r.OutputTokens
+		s.CostUSD += r.CostUSD
+	}
+	s.CategoryAccuracy = ratio(categoryHits, len(results))
+	s.PriorityAccuracy = ratio(priorityHits, len(results))
+	s.Categories = classMetrics(results, func(r result) (string, string) { return r.ExpectedCategory, r.Category })
+	s.Priorities = classMetrics(results, func(r result) (string, string) { return r.ExpectedPriority, r.Priority })
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	s.LatencyP50MS = percentile(latencies, 0.50)
+	s.LatencyP95MS = percentile(latencies, 0.95)
+	return s
+}
+
+// classMetrics computes precision/recall/F1 per expected or predicted value
+func classMetrics(results []result, labels func(result) (expected, predicted string)) []classMetric {
+	support, predicted, correct := map[string]int{}, map[string]int{}, map[string]int{}
+	for _, r := range results {
+		exp, pred := labels(r)
+		support[exp]++
+		if r.Error != "" {
+			continue // Counts as a miss for recall only
+		}
+		predicted[pred]++
+		if exp == pred {
+			correct[exp]++
+		}
+	}
+
+	names := map[string]bool{}
+	for n := range support {
+		names[n] = true
+	}
+	for n := range predicted {
+		names[n] = true
+	}
+	metrics := []classMetric{}
+	for n := range names {
+		m := classMetric{Name: n, Support: support[n], Precision: ratio(correct[n], predicted[n]), Recall: ratio(correct[n], support[n])}
+		if m.Precision+m.Recall > 0 {
+			m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+		}
+		metrics = append(metrics, m)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+func printSummary(w io.Writer, run *evalRun) {
+	s := run.Summary
+	fmt.Fprintf(w, "%s / %s / %s on %s\n", run.Provider, run.Model, run.PromptVersion, run.Dataset)
+	fmt.Fprintf(w, "Examples: %d  errors: %d  invalid output: %d\n", s.Examples, s.Errors, s.Invalid)
+	fmt.Fprintf(w, "Category accuracy: %.1f%%  priority accuracy: %.1f%%\n", s.CategoryAccuracy*100, s.PriorityAccuracy*100)
+	fmt.Fprintf(w, "Latency p50: %dms  p95: %dms\n", s.LatencyP50MS, s.LatencyP95MS)
+	fmt.Fprintf(w, "Tokens: %d in / %d out  cost: $%.4f\n", s.InputTokens, s.OutputTokens, s.CostUSD)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, section := range []struct {
+		title   string
+		metrics []classMetric
+	}{{"Category", s.Categories}, {"Priority", s.Priorities}} {
+		fmt.Fprintf(tw, "\n%s\tsupport\tprecision\trecall\tf1\n", section.title)
+		for _, m := range section.metrics {
+			fmt.Fprintf(tw, "%s\t%d\t%.2f\t%.2f\t%.2f\n", m.Name, m.Support, m.Precision, m.Recall, m.F1)
+		}
+	}
+	tw.Flush()
+}
+
+// printDiff compares run with a baseline: headline deltas, per-class F1
+// changes and the examples that flipped between right and wrong
+func printDiff(w io.Writer, baseline, run *evalRun) {
+	b, s := baseline.Summary, run.Summary
+	fmt.Fprintf(w, "\nVs baseline %s / %s (%s):\n", baseline.Model, baseline.PromptVersion, baseline.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Category accuracy: %+.1f pts  priority accuracy: %+.1f pts\n",
+		(s.CategoryAccuracy-b.CategoryAccuracy)*100, (s.PriorityAccuracy-b.PriorityAccuracy)*100)
+	fmt.Fprintf(w, "Latency p50: %+dms  p95: %+dms  cost: %+.4f USD\n",
+		s.LatencyP50MS-b.LatencyP50MS, s.LatencyP95MS-b.LatencyP95MS, s.CostUSD-b.CostUSD)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "\nCategory\tf1\tbaseline\tdelta\n")
+	before := map[string]classMetric{}
+	for _, m := range b.Categories {
+		before[m.Name] = m
+	}
+	for _, m := range s.Categories {
+		fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t%+.2f\n", m.Name, m.F1, before[m.Name].F1, m.F1-before[m.Name].F1)
+	}
+	tw.Flush()
+
+	previous := map[string]result{}
+	for _, r := range baseline.Results {
+		previous[r.ID] = r
+	}
+	var fixed, broke []string
+	for _, r := range run.Results {
+		p, ok := previous[r.ID]
+		if !ok {
+			continue
+		}
+		switch {
+		case r.categoryCorrect() && !p.categoryCorrect():
+			fixed = append(fixed, fmt.Sprintf("%s (%s -> %s)", r.ID, p.Category, r.Category))
+		case !r.categoryCorrect() && p.categoryCorrect():
+			broke = append(broke, fmt.Sprintf("%s (expected %s, got %s)", r.ID, r.ExpectedCategory, r.Category))
+		}
+	}
+	fmt.Fprintf(w, "\nNow correct (%d):\n", len(fixed))
+	for _, f := range fixed {
+		fmt.Fprintf(w, "  %s\n", f)
+	}
+	fmt.Fprintf(w, "Now wrong (%d):\n", len(broke))
+	for _, b := range broke {
+		fmt.Fprintf(w, "  %s\n", b)
+	}
+}
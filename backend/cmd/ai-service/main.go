@@ -1,13 +1,56 @@
 package main
 
 import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/redis"
 	"ai-ticketing-backend/services/ai"
 	"ai-ticketing-backend/services/ai/consumer"
+	"ai-ticketing-backend/services/ai/handlers"
+	"ai-ticketing-backend/services/ai/middleware"
+	"ai-ticketing-backend/services/ai/requeue"
 	"log"
+	"os"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	log.Println("Starting AI Service...")
-	svc := ai.Setup()
-	consumer.StartConsumer(svc)
+	cfg, err := config.Load("ai", os.Args[1:])
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	log.Printf("Loaded config:\n%s", cfg)
+	svc := ai.Setup(cfg)
+	go requeue.Start(svc, cfg.AI.BreakerCooldown)
+
+	h := handlers.NewAIHandlers(svc)
+	r := gin.New()
+	r.Use(cors.Default())
+	r.Use(gin.Logger())
+	r.Use(gin.Recovery())
+	r.SetTrustedProxies(nil)
+
+	// Agent-facing AI tools
+	agentApi := r.Group("/api/v1/agent/tickets")
+	agentApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.AgentAuthMiddleware())
+	{
+		agentApi.POST("/:id/translate", h.Translate)
+		agentApi.POST("/:id/summarize", h.Summarize)
+		agentApi.POST("/:id/reclassify", h.Reclassify)
+		agentApi.POST("/:id/draft-reply", h.DraftReply)
+		agentApi.POST("/:id/copilot", h.Copilot)
+		agentApi.GET("/:id/copilot/:conversationId", h.Conversation)
+	}
+
+	log.Printf("AI Service API on :%s", cfg.HTTP.Port)
+	go func() {
+		if err := r.Run(":" + cfg.HTTP.Port); err != nil {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Returns on SIGINT/SIGTERM once in-flight events are done
+	consumer.StartConsumer(svc, redis.New(cfg.Redis.Addr), cfg.Kafka, cfg.AI)
 }
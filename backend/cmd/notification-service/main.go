@@ -1,13 +1,64 @@
 package main
 
 import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/metrics"
 	"ai-ticketing-backend/services/notification"
 	"ai-ticketing-backend/services/notification/consumer"
+	"ai-ticketing-backend/services/notification/handlers"
+	"ai-ticketing-backend/services/notification/middleware"
 	"log"
+	"os"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	log.Println("Starting Notification Service...")
-	svc := notification.Setup()
-	consumer.StartConsumer(svc)
+	cfg, err := config.Load("notification", os.Args[1:])
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	log.Printf("Loaded config:\n%s", cfg)
+	svcs := notification.Setup(cfg)
+	ih := handlers.NewInboxHandlers(svcs.Inbox)
+
+	r := gin.New()
+	r.Use(cors.Default())
+	r.Use(gin.Logger())
+	r.Use(gin.Recovery())
+	r.SetTrustedProxies(nil)
+	r.Use(metricsMiddleware)
+
+	// In-app inbox of the signed-in user
+	inboxApi := r.Group("/api/v1/notifications")
+	inboxApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+	{
+		inboxApi.GET("", ih.List)
+		inboxApi.GET("/unread-count", ih.UnreadCount)
+		inboxApi.POST("/:id/read", ih.MarkRead)
+		inboxApi.POST("/read-all", ih.MarkAllRead)
+	}
+
+	metrics.RegisterMetrics() // /metrics endpoint
+
+	go svcs.Dispatcher.RetryEvery(cfg.Notification.DeliveryRetryInterval)
+	go func() {
+		consumer.StartConsumer(svcs, cfg.Kafka) // Returns on SIGINT/SIGTERM
+		os.Exit(0)
+	}()
+	log.Printf("Serving notifications on :%s", cfg.HTTP.Port)
+	if err := r.Run(":" + cfg.HTTP.Port); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
+
+// Metrics middleware
+func metricsMiddleware(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	duration := time.Since(start).Seconds()
+	metrics.RecordRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), duration)
 }
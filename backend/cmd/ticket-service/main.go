@@ -1,14 +1,17 @@
 package main
 
 import (
+	"ai-ticketing-backend/internal/pkg/config"
 	"ai-ticketing-backend/internal/pkg/metrics"
 	"ai-ticketing-backend/internal/pkg/redis"
 	"ai-ticketing-backend/services/ticket"
+	"ai-ticketing-backend/services/ticket/autoclose"
 	"ai-ticketing-backend/services/ticket/consumer"
 	"ai-ticketing-backend/services/ticket/handlers"
 	"ai-ticketing-backend/services/ticket/invalidator"
 	"ai-ticketing-backend/services/ticket/middleware"
 	"log"
+	"os"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -16,46 +19,137 @@ import (
 )
 
 func main() {
-	log.Println("Starting Ticket Service on :8081...")
-	svc, dbConn := ticket.Setup()
+	cfg, err := config.Load("ticket", os.Args[1:])
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	log.Printf("Loaded config:\n%s", cfg)
+	log.Printf("Starting Ticket Service on :%s...", cfg.HTTP.Port)
+	svcs, dbConn := ticket.Setup(cfg)
 	if err := dbConn.Migrate(); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
-	h := handlers.NewTicketHandlers(svc)
+	h := handlers.NewTicketHandlers(svcs.Tickets)
+	ah := handlers.NewAttachmentHandlers(svcs.Attachments)
+	ch := handlers.NewCommentHandlers(svcs.Comments)
+	th := handlers.NewTaxonomyHandlers(svcs.Taxonomy)
+	ph := handlers.NewPromptHandlers(svcs.Prompts)
+	kh := handlers.NewArticleHandlers(svcs.Articles)
+	clh := handlers.NewClassificationHandlers(svcs.Classifications)
+	sh := handlers.NewStreamHandlers(svcs.Realtime, cfg.Tickets.StreamHeartbeat)
 
 	r := gin.New()
 	r.Use(cors.Default()) // Add CORS middleware
-	r.Use(gin.Logger())
+	r.Use(middleware.Logger())
 	r.Use(gin.Recovery())
 	r.SetTrustedProxies(nil)
 	r.Use(metricsMiddleware) // Metrics for requests
 
 	// Customer routes
 	customerApi := r.Group("/api/v1/tickets")
-	customerApi.Use(middleware.AuthMiddleware())
+	customerApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
 	{
 		customerApi.POST("/", h.Create)
 		customerApi.GET("/:id", h.GetByID)
 		customerApi.GET("/", h.ListByUser)
 		customerApi.PUT("/:id/customer", h.CustomerUpdate)
+		customerApi.POST("/:id/confirm", h.ConfirmResolution)
+		customerApi.POST("/:id/attachments", ah.Upload)
+		customerApi.GET("/:id/attachments", ah.List)
+		customerApi.GET("/:id/attachments/:attachment_id/url", ah.SignedURL)
+		customerApi.POST("/:id/comments", ch.Add)
+		customerApi.GET("/:id/comments", ch.List)
 	}
 
 	// Agent routes
 	agentApi := r.Group("/api/v1/agent/tickets")
-	agentApi.Use(middleware.AuthMiddleware(), middleware.AgentAuthMiddleware())
+	agentApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.AgentAuthMiddleware())
 	{
 		agentApi.GET("/", h.ListAll)
 		agentApi.GET("/:id", h.GetByID)
 		agentApi.PUT("/:id", h.Update)
+		agentApi.GET("/:id/similar", h.Similar)
+		agentApi.POST("/:id/attachments", ah.Upload)
+		agentApi.GET("/:id/attachments", ah.List)
+		agentApi.GET("/:id/attachments/:attachment_id/url", ah.SignedURL)
+		agentApi.POST("/:id/comments", ch.Add)
+		agentApi.GET("/:id/comments", ch.List)
+		agentApi.GET("/:id/classifications", clh.ListByTicket)
+		agentApi.POST("/:id/classifications/:classification_id/review", clh.Review)
+	}
+	// Knowledge base: published articles are public (?q= searches), agents manage them
+	articleApi := r.Group("/api/v1/articles")
+	{
+		articleApi.GET("", kh.List)
+		articleApi.GET("/:id", kh.Get)
+	}
+	agentArticleApi := r.Group("/api/v1/agent/articles")
+	agentArticleApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.AgentAuthMiddleware())
+	{
+		agentArticleApi.GET("", kh.List)
+		agentArticleApi.GET("/:id", kh.Get)
+		agentArticleApi.POST("", kh.Create)
+		agentArticleApi.PUT("/:id", kh.Update)
+		agentArticleApi.DELETE("/:id", kh.Delete)
+	}
+	agentClassificationApi := r.Group("/api/v1/agent/classifications")
+	agentClassificationApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.AgentAuthMiddleware())
+	{
+		agentClassificationApi.GET("/pending", clh.ListPending)
+	}
+	agentReportApi := r.Group("/api/v1/agent/reports")
+	agentReportApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.AgentAuthMiddleware())
+	{
+		agentReportApi.GET("/classification", clh.Report)
+	}
+
+	// Taxonomy: readable by any signed-in user, managed by admins
+	taxonomyApi := r.Group("/api/v1/taxonomy")
+	taxonomyApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+	{
+		taxonomyApi.GET("/categories", th.ListCategories)
+		taxonomyApi.GET("/priorities", th.ListPriorities)
+	}
+	adminApi := r.Group("/api/v1/admin/taxonomy")
+	adminApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.AdminAuthMiddleware())
+	{
+		adminApi.POST("/categories", th.CreateCategory)
+		adminApi.PUT("/categories/:id", th.UpdateCategory)
+		adminApi.DELETE("/categories/:id", th.DeleteCategory)
+		adminApi.POST("/priorities", th.CreatePriority)
+		adminApi.PUT("/priorities/:id", th.UpdatePriority)
+		adminApi.DELETE("/priorities/:id", th.DeletePriority)
+	}
+	// Prompt templates: new versions start as candidates; activating an older one rolls back
+	promptApi := r.Group("/api/v1/admin/prompts")
+	promptApi.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.AdminAuthMiddleware())
+	{
+		promptApi.GET("", ph.List)
+		promptApi.POST("", ph.Create)
+		promptApi.POST("/:id/activate", ph.Activate)
 	}
 
+	// Realtime ticket updates (SSE) for customers and agents; EventSource
+	// can't send headers, so the token may come as ?access_token=
+	streamApi := r.Group("/api/v1/stream")
+	streamApi.Use(middleware.QueryTokenMiddleware(), middleware.AuthMiddleware(cfg.JWT.Secret))
+	{
+		streamApi.GET("", sh.Stream)
+	}
+
+	// Signed download links (authorized by the URL signature, not a JWT)
+	r.GET("/api/v1/attachments/:attachment_id/download", ah.Download)
+
 	metrics.RegisterMetrics() // /metrics endpoint
 
-	cache := redis.New()
-	go invalidator.StartInvalidator(cache)
+	cache := redis.New(cfg.Redis.Addr)
+	go invalidator.StartInvalidator(cache, cfg.Kafka)
 
-	go consumer.StartConsumer()
-	if err := r.Run(":8081"); err != nil {
+	go consumer.StartConsumer(cfg.Kafka)
+	go svcs.Realtime.Start(cfg.Kafka)
+	go svcs.Realtime.StartNotifications(cache)
+	go autoclose.Start(svcs.Tickets, cfg.Tickets.AutoCloseAfter)
+	if err := r.Run(":" + cfg.HTTP.Port); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
@@ -1,18 +1,25 @@
 package main
 
 import (
+	"ai-ticketing-backend/internal/pkg/config"
 	"ai-ticketing-backend/services/user"
 	"ai-ticketing-backend/services/user/handlers"
 	"ai-ticketing-backend/services/user/middleware"
 	"log"
+	"os"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	log.Println("Starting User Service on :8080...")
-	svc := user.Setup()
+	cfg, err := config.Load("user", os.Args[1:])
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+	log.Printf("Loaded config:\n%s", cfg)
+	log.Printf("Starting User Service on :%s...", cfg.HTTP.Port)
+	svc := user.Setup(cfg)
 	h := handlers.NewUserHandlers(svc)
 
 	r := gin.Default()
@@ -29,9 +36,11 @@ func main() {
 	{
 		protected.GET("/:id", h.GetUser)
 		protected.GET("/", h.ListUsers)
+		protected.POST("/", middleware.AdminAuthMiddleware(), h.CreateUser)
+		protected.PATCH("/:id", middleware.AdminAuthMiddleware(), h.UpdateUser)
 	}
 
-	if err := r.Run(":8080"); err != nil {
+	if err := r.Run(":" + cfg.HTTP.Port); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Article is a knowledge base entry. Published articles are searchable by
+// customers and retrieved by the AI service to ground its suggestions.
+type Article struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Title     string     `json:"title" gorm:"not null"`
+	Body      string     `json:"body" gorm:"type:text;not null"`
+	Category  string     `json:"category"` // Optional, one of the taxonomy categories
+	Tags      []string   `json:"tags" gorm:"type:text;serializer:json"`
+	Published bool       `json:"published" gorm:"default:false;index"`
+	AuthorID  *uuid.UUID `json:"author_id,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// CreateArticleRequest for agents writing an article
+type CreateArticleRequest struct {
+	Title     string   `json:"title" binding:"required,min=5"`
+	Body      string   `json:"body" binding:"required,min=20"`
+	Category  string   `json:"category"`
+	Tags      []string `json:"tags"`
+	Published bool     `json:"published"`
+}
+
+// UpdateArticleRequest changes only the fields that are set
+type UpdateArticleRequest struct {
+	Title     *string   `json:"title,omitempty"`
+	Body      *string   `json:"body,omitempty"`
+	Category  *string   `json:"category,omitempty"`
+	Tags      *[]string `json:"tags,omitempty"`
+	Published *bool     `json:"published,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file uploaded to a ticket; the bytes live in the blob store
+type Attachment struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TicketID    uuid.UUID `json:"ticket_id" gorm:"type:uuid;not null;index"`
+	UploaderID  uuid.UUID `json:"uploader_id" gorm:"type:uuid;not null"`
+	Filename    string    `json:"filename" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"not null"`
+	Size        int64     `json:"size" gorm:"not null"`
+	StorageKey  string    `json:"-" gorm:"not null"` // Key in the BlobStore, never exposed
+	CreatedAt   time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// AttachmentURLResponse carries a short-lived signed download link
+type AttachmentURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Classification review states
+const (
+	ClassificationPending     = "pending"      // Below the auto-apply threshold, waiting for an agent
+	ClassificationAutoApplied = "auto_applied" // Confident enough to be written to the ticket directly
+	ClassificationAccepted    = "accepted"
+	ClassificationEdited      = "edited"
+	ClassificationRejected    = "rejected"
+	ClassificationSuperseded  = "superseded" // A newer run replaced it before anyone reviewed it
+)
+
+// AIClassification records one AI run on a ticket: what the model proposed
+// and what an agent did with it.
+type AIClassification struct {
+	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TicketID      uuid.UUID   `json:"ticket_id" gorm:"type:uuid;not null;index"`
+	Provider      string      `json:"provider"`
+	Model         string      `json:"model"`
+	PromptVersion string      `json:"prompt_version"`
+	RawOutput     string      `json:"raw_output" gorm:"type:text"`
+	Valid         bool        `json:"valid"` // False when the output failed validation and defaults were proposed
+	Category      string      `json:"category"`
+	Priority      string      `json:"priority"`
+	Suggestion    string      `json:"suggestion" gorm:"type:text"`
+	Confidence    float64     `json:"confidence"`
+	Sentiment     string      `json:"sentiment,omitempty"`
+	Urgency       float64     `json:"urgency"`
+	Language      string      `json:"language,omitempty"`
+	Escalation    string      `json:"escalation,omitempty"`                                   // Escalation rule that raised Priority above the model's answer
+	ArticleIDs    []uuid.UUID `json:"article_ids,omitempty" gorm:"type:text;serializer:json"` // Knowledge base articles cited in Suggestion
+	Status        string      `json:"status" gorm:"not null;default:pending;index"`
+	InputTokens   int         `json:"input_tokens"` // Summed over the repair re-prompt, if any
+	OutputTokens  int         `json:"output_tokens"`
+	LatencyMS     int64       `json:"latency_ms"`
+	Cached        bool        `json:"cached"`         // Reused the result of an identical prompt; no tokens spent
+	ContentHash   string      `json:"-" gorm:"index"` // Hash of the normalized prompt, to skip unchanged tickets
+
+	// Filled in when an agent reviews the run
+	ReviewedBy      *uuid.UUID `json:"reviewed_by,omitempty" gorm:"type:uuid"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	FinalCategory   string     `json:"final_category,omitempty"`
+	FinalPriority   string     `json:"final_priority,omitempty"`
+	FinalSuggestion string     `json:"final_suggestion,omitempty" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// ReviewClassificationRequest is an agent's decision on a classification
+type ReviewClassificationRequest struct {
+	Action     string  `json:"action" binding:"required,oneof=accept edit reject"`
+	Category   *string `json:"category,omitempty"` // Edit only
+	Priority   *string `json:"priority,omitempty"`
+	Suggestion *string `json:"suggestion,omitempty"`
+}
+
+// ClassificationFeedback records an agent changing a value the AI set,
+// either while reviewing a run or by editing the ticket afterwards.
+type ClassificationFeedback struct {
+	ID               uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ClassificationID uuid.UUID `json:"classification_id" gorm:"type:uuid;not null;index"`
+	TicketID         uuid.UUID `json:"ticket_id" gorm:"type:uuid;not null;index"`
+	AgentID          uuid.UUID `json:"agent_id" gorm:"type:uuid;not null"`
+	Field            string    `json:"field"` // "category" or "priority"
+	AIValue          string    `json:"ai_value"`
+	AgentValue       string    `json:"agent_value"`
+	CreatedAt        time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// ClassificationReport is the accuracy report for agents, one group per
+// model and prompt version so prompt changes can be compared.
+type ClassificationReport struct {
+	From     time.Time                   `json:"from"`
+	To       time.Time                   `json:"to"`
+	Interval string                      `json:"interval"`
+	Groups   []ClassificationReportGroup `json:"groups"`
+}
+
+type ClassificationReportGroup struct {
+	Model         string  `json:"model"`
+	PromptVersion string  `json:"prompt_version"`
+	Runs          int     `json:"runs"`
+	Labeled       int     `json:"labeled"` // Runs with a known correct category (reviewed or auto-applied)
+	Overridden    int     `json:"overridden"`
+	OverrideRate  float64 `json:"override_rate"`
+	Accuracy      float64 `json:"accuracy"` // Category accuracy over labeled runs
+
+	Categories        []ClassMetrics            `json:"categories"`
+	CategoryConfusion map[string]map[string]int `json:"category_confusion"` // Actual -> predicted -> count
+	Priorities        []ClassMetrics            `json:"priorities"`
+	PriorityConfusion map[string]map[string]int `json:"priority_confusion"`
+
+	OverrideSeries []OverridePoint `json:"override_series"`
+}
+
+// ClassMetrics is precision/recall for a single category or priority
+type ClassMetrics struct {
+	Name      string  `json:"name"`
+	Support   int     `json:"support"` // Labeled runs whose actual value is Name
+	Predicted int     `json:"predicted"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+}
+
+// OverridePoint is the override rate for one period
+type OverridePoint struct {
+	Period     time.Time `json:"period"`
+	Runs       int       `json:"runs"`
+	Overridden int       `json:"overridden"`
+	Rate       float64   `json:"rate"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment is a message on a ticket thread; non-public comments are agent-only notes
+type Comment struct {
+	ID         uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TicketID   uuid.UUID `json:"ticket_id" gorm:"type:uuid;not null;index"`
+	AuthorID   uuid.UUID `json:"author_id" gorm:"type:uuid;not null"`
+	AuthorRole string    `json:"author_role" gorm:"not null"` // "customer", "agent" or "ai"
+	Body       string    `json:"body" gorm:"type:text;not null"`
+	Public     bool      `json:"public" gorm:"default:true"`
+	CreatedAt  time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// CreateCommentRequest for new comments; Public defaults to true and only agents may set false
+type CreateCommentRequest struct {
+	Body    string     `json:"body" binding:"required,min=1"`
+	Public  *bool      `json:"public,omitempty"`
+	DraftID *uuid.UUID `json:"draft_id,omitempty"` // AI reply draft the body was written from, to track how drafts are used
+}
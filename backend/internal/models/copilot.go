@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Copilot message roles
+const (
+	CopilotAgent     = "agent"
+	CopilotAssistant = "assistant"
+	CopilotTool      = "tool" // A tool's result, kept so follow-up questions can use it
+)
+
+// CopilotConversation is an agent's chat with the AI about one ticket
+type CopilotConversation struct {
+	ID        uuid.UUID        `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TicketID  uuid.UUID        `json:"ticket_id" gorm:"type:uuid;not null;index"`
+	AgentID   uuid.UUID        `json:"agent_id" gorm:"type:uuid;not null;index"`
+	Messages  []CopilotMessage `json:"messages,omitempty" gorm:"foreignKey:ConversationID"`
+	CreatedAt time.Time        `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt time.Time        `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// CopilotMessage is one message of a copilot conversation
+type CopilotMessage struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	ConversationID uuid.UUID `json:"conversation_id" gorm:"type:uuid;not null;index"`
+	Role           string    `json:"role" gorm:"not null"`
+	Tool           string    `json:"tool,omitempty"`  // Tool messages: which tool ran
+	Query          string    `json:"query,omitempty"` // and what it was asked
+	Content        string    `json:"content" gorm:"type:text"`
+	InputTokens    int       `json:"input_tokens,omitempty"`
+	OutputTokens   int       `json:"output_tokens,omitempty"`
+	CreatedAt      time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// CopilotRequest is an agent's question; without ConversationID a new conversation starts
+type CopilotRequest struct {
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	Message        string     `json:"message" binding:"required,min=1,max=4000"`
+}
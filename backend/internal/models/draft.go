@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reply draft tones
+const (
+	ToneFormal   = "formal"
+	ToneFriendly = "friendly"
+	ToneConcise  = "concise"
+)
+
+// What the agent did with a reply draft
+const (
+	DraftPending = "pending" // Not sent (yet)
+	DraftUsed    = "used"    // Sent as written
+	DraftEdited  = "edited"  // Sent after changes
+)
+
+// DraftReplyRequest asks the AI for a reply to the customer; Tone defaults to friendly
+type DraftReplyRequest struct {
+	Tone         string `json:"tone,omitempty" binding:"omitempty,oneof=formal friendly concise"`
+	Instructions string `json:"instructions,omitempty"` // Extra guidance from the agent, e.g. "offer a refund"
+}
+
+// ReplyDraft is an AI-written reply and, once sent, what the agent did with it
+type ReplyDraft struct {
+	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TicketID      uuid.UUID   `json:"ticket_id" gorm:"type:uuid;not null;index"`
+	AgentID       uuid.UUID   `json:"agent_id" gorm:"type:uuid;not null"`
+	Tone          string      `json:"tone"`
+	Language      string      `json:"language,omitempty"`
+	Body          string      `json:"body" gorm:"type:text"`
+	ArticleIDs    []uuid.UUID `json:"article_ids,omitempty" gorm:"type:text;serializer:json"` // Knowledge base articles offered to the model
+	Model         string      `json:"model"`
+	PromptVersion string      `json:"prompt_version"`
+	InputTokens   int         `json:"input_tokens"`
+	OutputTokens  int         `json:"output_tokens"`
+	Outcome       string      `json:"outcome" gorm:"not null;default:pending;index"`
+	CommentID     *uuid.UUID  `json:"comment_id,omitempty" gorm:"type:uuid"` // The comment it was sent as
+	SentAt        *time.Time  `json:"sent_at,omitempty"`
+	CreatedAt     time.Time   `json:"created_at" gorm:"default:current_timestamp"`
+}
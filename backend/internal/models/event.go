@@ -1,9 +1,63 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types, carried in each event's "type" field
+const (
+	EventTicketCreated        = "ticket_created"
+	EventTicketUpdated        = "ticket_updated"
+	EventTicketContentUpdated = "ticket_content_updated"
+	EventTicketEscalated      = "ticket_escalated"
+	EventTicketAssigned       = "ticket_assigned"
+)
+
+// EventType reads the type of a ticket-events message. Messages published
+// before events carried a type are recognized by their fields.
+func EventType(data []byte) string {
+	var probe struct {
+		Type      string  `json:"type"`
+		NewStatus *string `json:"new_status"`
+		CreatedAt *string `json:"created_at"`
+		UpdatedAt *string `json:"updated_at"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return ""
+	}
+	switch {
+	case probe.Type != "":
+		return probe.Type
+	case probe.NewStatus != nil:
+		return EventTicketUpdated
+	case probe.CreatedAt != nil:
+		return EventTicketCreated
+	case probe.UpdatedAt != nil:
+		return EventTicketContentUpdated
+	}
+	return ""
+}
+
+// EventID reads the id of a ticket-events message. Messages published
+// before events carried an id are identified by their position in the topic.
+func EventID(data []byte, topic string, partition int, offset int64) string {
+	var probe struct {
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(data, &probe); err == nil && probe.EventID != "" {
+		return probe.EventID
+	}
+	return fmt.Sprintf("%s-%d-%d", topic, partition, offset)
+}
 
 // TicketCreatedEvent for Kafka
 type TicketCreatedEvent struct {
+	Type        string    `json:"type"`               // EventTicketCreated
+	EventID     string    `json:"event_id,omitempty"` // Unique per publish; consumers use it to skip redeliveries
 	TicketID    uuid.UUID `json:"ticket_id"`
 	UserID      uuid.UUID `json:"user_id"`
 	Title       string    `json:"title"`
@@ -12,6 +66,8 @@ type TicketCreatedEvent struct {
 }
 
 type TicketUpdatedEvent struct {
+	Type      string    `json:"type"` // EventTicketUpdated
+	EventID   string    `json:"event_id,omitempty"`
 	TicketID  uuid.UUID `json:"ticket_id"`
 	UserID    uuid.UUID `json:"user_id"`
 	OldStatus string    `json:"old_status"`
@@ -19,11 +75,57 @@ type TicketUpdatedEvent struct {
 	UpdatedAt string    `json:"updated_at"`
 }
 
-// TicketContentUpdatedEvent is published when a ticket's content (title or description) is updated by the customer
+// TicketContentUpdatedEvent is published when a ticket's content (title or description) is updated by the customer,
+// or when a comment is added (CommentID set)
 type TicketContentUpdatedEvent struct {
+	Type        string     `json:"type"` // EventTicketContentUpdated
+	EventID     string     `json:"event_id,omitempty"`
+	TicketID    uuid.UUID  `json:"ticket_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	CommentID   *uuid.UUID `json:"comment_id,omitempty"`
+	AuthorID    *uuid.UUID `json:"author_id,omitempty"`   // Comment author
+	AuthorRole  string     `json:"author_role,omitempty"` // "customer", "agent" or "ai"
+	Internal    bool       `json:"internal,omitempty"`    // The comment is an internal note, hidden from the customer
+	UpdatedAt   string     `json:"updated_at"`
+}
+
+// TicketEscalatedEvent is published by the AI service when an escalation
+// rule raises a ticket's priority
+type TicketEscalatedEvent struct {
+	Type        string    `json:"type"` // EventTicketEscalated
+	EventID     string    `json:"event_id,omitempty"`
 	TicketID    uuid.UUID `json:"ticket_id"`
 	UserID      uuid.UUID `json:"user_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	UpdatedAt   string    `json:"updated_at"`
+	OldPriority string    `json:"old_priority"`
+	NewPriority string    `json:"new_priority"`
+	Sentiment   string    `json:"sentiment"`
+	Urgency     float64   `json:"urgency"`
+	Rule        string    `json:"rule"` // The rule that matched, as configured
+	EscalatedAt string    `json:"escalated_at"`
+}
+
+// TicketAssignedEvent is published when a ticket is assigned to an agent
+type TicketAssignedEvent struct {
+	Type       string    `json:"type"` // EventTicketAssigned
+	EventID    string    `json:"event_id,omitempty"`
+	TicketID   uuid.UUID `json:"ticket_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	AgentID    uuid.UUID `json:"agent_id"`
+	AssignedBy uuid.UUID `json:"assigned_by"` // The agent themselves when they pick the ticket up
+	AssignedAt string    `json:"assigned_at"`
+}
+
+// TicketUpdate is pushed to clients on the realtime stream when a ticket
+// changes, with the ticket's state after the change
+type TicketUpdate struct {
+	ID        string     `json:"id"`   // Event id; send it as Last-Event-ID to resume after reconnecting
+	Type      string     `json:"type"` // The ticket-events type, e.g. EventTicketUpdated
+	TicketID  uuid.UUID  `json:"ticket_id"`
+	Status    string     `json:"status"`
+	Category  string     `json:"category"`
+	Priority  string     `json:"priority"`
+	CommentID *uuid.UUID `json:"comment_id,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification kinds
+const (
+	NotificationAssignment = "assignment"
+	NotificationStatus     = "status"
+	NotificationComment    = "comment"
+)
+
+// NotificationChannel is the Redis pub/sub channel new notifications are
+// published on, for realtime delivery
+const NotificationChannel = "notifications"
+
+// Notification is an entry in a user's in-app inbox
+type Notification struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_notification_event"`
+	TicketID  uuid.UUID  `json:"ticket_id" gorm:"type:uuid;not null"`
+	Kind      string     `json:"kind" gorm:"not null"`
+	Title     string     `json:"title" gorm:"not null"`
+	Body      string     `json:"body" gorm:"type:text"`
+	EventID   string     `json:"-" gorm:"not null;uniqueIndex:idx_notification_event"` // A redelivered event doesn't notify twice
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// NotificationList is a page of a user's inbox, newest first
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+}
+
+// Delivery statuses
+const (
+	DeliveryPending = "pending" // Queued for its channel
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed" // Retried periodically until the attempts run out
+)
+
+// NotificationDelivery tracks one event's delivery through one channel
+type NotificationDelivery struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	EventID     string     `json:"event_id" gorm:"not null;uniqueIndex:idx_delivery_event_channel"`
+	Channel     string     `json:"channel" gorm:"not null;uniqueIndex:idx_delivery_event_channel"`
+	EventType   string     `json:"event_type"`
+	TicketID    uuid.UUID  `json:"ticket_id" gorm:"type:uuid"`
+	Status      string     `json:"status" gorm:"not null;index"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty" gorm:"type:text"`
+	Payload     string     `json:"-" gorm:"type:text"` // The event as published, to retry from
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"default:current_timestamp"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Prompt template states. Only one template per name and tenant is active;
+// candidates are drafts that can be evaluated before activation.
+const (
+	PromptActive    = "active"
+	PromptCandidate = "candidate"
+	PromptRetired   = "retired"
+)
+
+// PromptTemplate is a stored version of an LLM prompt. The embedded
+// defaults in internal/pkg/prompt are used when no version is active.
+type PromptTemplate struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name        string     `json:"name" gorm:"not null;index;uniqueIndex:idx_prompt_version"` // e.g. "classify"
+	Version     int        `json:"version" gorm:"not null;uniqueIndex:idx_prompt_version"`    // Numbered per name and tenant
+	Tenant      string     `json:"tenant" gorm:"index;uniqueIndex:idx_prompt_version"`        // Empty applies to every tenant without its own override
+	Body        string     `json:"body" gorm:"type:text;not null"`
+	Status      string     `json:"status" gorm:"not null;default:candidate"`
+	Notes       string     `json:"notes"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"default:current_timestamp"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+}
+
+// CreatePromptRequest adds a new candidate version
+type CreatePromptRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Tenant string `json:"tenant"`
+	Body   string `json:"body" binding:"required"`
+	Notes  string `json:"notes"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Similarity kinds, by score against the configured thresholds
+const (
+	SimilarityDuplicate = "duplicate"
+	SimilarityRelated   = "related"
+)
+
+// TicketEmbedding is the vector for a ticket's title and description.
+// Only vectors with the same Model are compared.
+type TicketEmbedding struct {
+	TicketID    uuid.UUID `json:"ticket_id" gorm:"type:uuid;primaryKey"`
+	Tenant      string    `json:"tenant" gorm:"index"`
+	Model       string    `json:"model" gorm:"not null;index"`
+	Vector      []float32 `json:"-" gorm:"type:text;serializer:json"`
+	ContentHash string    `json:"content_hash"` // Skips re-embedding unchanged content
+	CreatedAt   time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// TicketSimilarity links a ticket to an earlier one found to be similar
+type TicketSimilarity struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	TicketID        uuid.UUID `json:"ticket_id" gorm:"type:uuid;not null;index"`
+	SimilarTicketID uuid.UUID `json:"similar_ticket_id" gorm:"type:uuid;not null;index"`
+	Score           float64   `json:"score"`
+	Kind            string    `json:"kind"`
+	CreatedAt       time.Time `json:"created_at" gorm:"default:current_timestamp"`
+}
+
+// SimilarTicket is one entry of GET /api/v1/agent/tickets/:id/similar
+type SimilarTicket struct {
+	TicketID  uuid.UUID `json:"ticket_id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	Category  string    `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+	Score     float64   `json:"score"`
+	Kind      string    `json:"kind"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TicketSummary is the AI's rolling summary of a ticket and its thread,
+// shown to agents only since it covers internal notes
+type TicketSummary struct {
+	TicketID      uuid.UUID `json:"ticket_id" gorm:"type:uuid;primaryKey"`
+	Summary       string    `json:"summary" gorm:"type:text"`
+	Comments      int       `json:"comments"` // Comments covered by the summary
+	ContentHash   string    `json:"-"`        // Of the title, description and comments; unchanged means no new LLM call
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"prompt_version"`
+	InputTokens   int       `json:"input_tokens"`
+	OutputTokens  int       `json:"output_tokens"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
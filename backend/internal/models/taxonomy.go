@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Category is an admin-managed ticket queue; descriptions and examples are fed to the AI prompt
+type Category struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	Examples    []string  `json:"examples" gorm:"type:text;serializer:json"` // Sample ticket titles
+	Active      bool      `json:"active" gorm:"default:true"`                // Inactive entries are kept for history but not offered
+	AutoResolve bool      `json:"auto_resolve" gorm:"default:false"`         // Let the AI answer confident, KB-backed tickets directly
+	CreatedAt   time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// Priority is an admin-managed priority level; Rank orders them (1 = lowest)
+type Priority struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	Examples    []string  `json:"examples" gorm:"type:text;serializer:json"`
+	Rank        int       `json:"rank" gorm:"not null;default:0"`
+	Active      bool      `json:"active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"default:current_timestamp"`
+}
+
+// TaxonomyEntryRequest creates a category or priority
+type TaxonomyEntryRequest struct {
+	Name        string   `json:"name" binding:"required,min=2,max=64"`
+	Description string   `json:"description"`
+	Examples    []string `json:"examples"`
+	Rank        int      `json:"rank"`         // Priorities only
+	AutoResolve bool     `json:"auto_resolve"` // Categories only
+}
+
+// UpdateTaxonomyEntryRequest for partial updates
+type UpdateTaxonomyEntryRequest struct {
+	Description *string   `json:"description,omitempty"`
+	Examples    *[]string `json:"examples,omitempty"`
+	Rank        *int      `json:"rank,omitempty"`
+	Active      *bool     `json:"active,omitempty"`
+	AutoResolve *bool     `json:"auto_resolve,omitempty"` // Categories only
+}
+
+// DefaultCategories and DefaultPriorities seed an empty database
+var DefaultCategories = []Category{
+	{Name: "Billing", Description: "Invoices, charges, refunds, payment methods and plans", Examples: []string{"I was charged twice this month", "How do I update my credit card?"}},
+	{Name: "Bug", Description: "Something in the product is broken or behaves incorrectly", Examples: []string{"App crashes when I upload a file", "Export button returns a 500 error"}},
+	{Name: "Feature", Description: "Requests for new functionality or changes to existing behaviour", Examples: []string{"Please add dark mode", "Can we get an API for reports?"}},
+	{Name: "Support", Description: "How-to questions, account access and general help", Examples: []string{"How do I reset my password?", "Where can I find my API key?"}},
+}
+
+var DefaultPriorities = []Priority{
+	{Name: "low", Rank: 1, Description: "Questions and minor issues with a workaround"},
+	{Name: "medium", Rank: 2, Description: "Degraded functionality affecting the customer's work"},
+	{Name: "high", Rank: 3, Description: "Outages, data loss, security or billing errors blocking the customer"},
+}
@@ -8,18 +8,30 @@ import (
 
 // Ticket represents a support ticket
 type Ticket struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	Title       string    `json:"title" gorm:"not null"`
-	Description string    `json:"description" gorm:"not null"`
-	Status      string    `json:"status" gorm:"default:open"`                  // e.g., "open", "in_progress", "closed"
-	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`           // Foreign key to User
-	User        User      `json:"user" gorm:"foreignKey:UserID;references:ID"` // Optional: Load user on query
-	CreatedAt   time.Time `json:"created_at" gorm:"default:current_timestamp"`
-	UpdatedAt   time.Time `json:"updated_at" gorm:"default:current_timestamp"`
-	Category    string    `json:"category" gorm:"default:''"`    // e.g., "Billing", "Bug"
-	Priority    string    `json:"priority" gorm:"default:'low'"` // "low", "medium", "high"
-	Suggestion  string    `json:"suggestion" gorm:"type:text"`   // AI reply suggestion
-	AgentID     *uuid.UUID `json:"agent_id,omitempty" gorm:"type:uuid"`
+	ID                    uuid.UUID    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Title                 string       `json:"title" gorm:"not null"`
+	Description           string       `json:"description" gorm:"not null"`
+	Status                string       `json:"status" gorm:"default:open"`                  // e.g., "open", "ai_queued", "ai_processing", "pending_review", "classified", "pending_customer", "in_progress", "closed"
+	UserID                uuid.UUID    `json:"user_id" gorm:"type:uuid;not null"`           // Foreign key to User
+	User                  User         `json:"user" gorm:"foreignKey:UserID;references:ID"` // Optional: Load user on query
+	CreatedAt             time.Time    `json:"created_at" gorm:"default:current_timestamp"`
+	UpdatedAt             time.Time    `json:"updated_at" gorm:"default:current_timestamp"`
+	Category              string       `json:"category" gorm:"default:''"`     // e.g., "Billing", "Bug"
+	Priority              string       `json:"priority" gorm:"default:'low'"`  // "low", "medium", "high"
+	Suggestion            string       `json:"suggestion" gorm:"type:text"`    // AI reply suggestion
+	AIConfidence          float64      `json:"ai_confidence" gorm:"default:0"` // 0-1, reported by the classifier
+	Sentiment             string       `json:"sentiment,omitempty"`            // "positive", "neutral", "frustrated" or "angry", set by the AI service
+	Urgency               float64      `json:"urgency" gorm:"default:0"`       // 0-1, how time-critical the issue is for the customer
+	Language              string       `json:"language,omitempty"`             // ISO 639-1 code of the language the customer wrote in
+	TranslatedTitle       string       `json:"translated_title,omitempty"`     // Title in the agents' language, when the customer wrote in another
+	TranslatedDescription string       `json:"translated_description,omitempty" gorm:"type:text"`
+	EscalatedAt           *time.Time   `json:"escalated_at,omitempty"` // When an escalation rule last raised the priority
+	AgentID               *uuid.UUID   `json:"agent_id,omitempty" gorm:"type:uuid"`
+	AutoResolvedAt        *time.Time   `json:"auto_resolved_at,omitempty"`                             // When the AI answered the customer directly
+	PendingCustomerSince  *time.Time   `json:"pending_customer_since,omitempty"`                       // When the ticket last started waiting for the customer
+	DuplicateOf           *uuid.UUID   `json:"duplicate_of,omitempty" gorm:"type:uuid"`                // Likely duplicate of this earlier ticket, set by the AI service
+	ArticleIDs            []uuid.UUID  `json:"article_ids,omitempty" gorm:"type:text;serializer:json"` // Knowledge base articles cited by the AI suggestion
+	Attachments           []Attachment `json:"attachments,omitempty" gorm:"foreignKey:TicketID"`
 }
 
 // CreateTicketRequest for incoming data
@@ -33,6 +45,9 @@ type UpdateTicketRequest struct {
 	Title       *string `json:"title,omitempty"`
 	Description *string `json:"description,omitempty"`
 	Status      *string `json:"status,omitempty"`
+	Category    *string `json:"category,omitempty"` // Must be an active Category name
+	Priority    *string `json:"priority,omitempty"` // Must be an active Priority name
+	Suggestion  *string `json:"suggestion,omitempty"`
 }
 
 // CustomerUpdateTicketRequest for customer updates
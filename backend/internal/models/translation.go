@@ -0,0 +1,13 @@
+package models
+
+// TranslateRequest asks the AI service to translate an agent's reply before it is sent
+type TranslateRequest struct {
+	Text     string `json:"text" binding:"required"`
+	Language string `json:"language,omitempty"` // ISO 639-1 target; defaults to the ticket's language
+}
+
+// TranslateResponse is the translated text
+type TranslateResponse struct {
+	Text     string `json:"text"`
+	Language string `json:"language"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AIUsage is one tenant's LLM usage for one UTC day, checked against the
+// daily token and cost budgets
+type AIUsage struct {
+	Tenant       string    `json:"tenant" gorm:"primaryKey"`
+	Day          string    `json:"day" gorm:"primaryKey"` // YYYY-MM-DD
+	Calls        int       `json:"calls"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	Cost         float64   `json:"cost"` // USD at the configured token prices
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -6,12 +6,24 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultTenant is assigned to self-registered users and to users an admin
+// creates without a tenant
+const DefaultTenant = "default"
+
+// User roles
+const (
+	RoleCustomer = "customer"
+	RoleAgent    = "agent"
+	RoleAdmin    = "admin"
+)
+
 // User represents a user in the system (e.g., customer or agent)
 type User struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"` // Auto-gen UUID
 	Email     string    `json:"email" gorm:"unique;not null"`                              // Unique email
 	Password  string    `json:"-" gorm:"not null"`                                         // Hide from JSON output
-	Role      string    `json:"role" gorm:"default:customer"`                              // e.g., "customer" or "agent"
+	Role      string    `json:"role" gorm:"default:customer"`                              // "customer", "agent" or "admin"
+	Tenant    string    `json:"tenant" gorm:"not null;default:default;index"`              // Organization the user belongs to
 	CreatedAt time.Time `json:"created_at" gorm:"default:current_timestamp"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"default:current_timestamp"`
 }
@@ -22,11 +34,27 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 }
 
-// RegisterRequest for incoming registration (validated)
+// RegisterRequest for incoming registration (validated); self-registered
+// users are always customers of the default tenant
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"omitempty,oneof=customer agent"` // Optional, defaults to customer
+}
+
+// CreateUserRequest is how admins add agents and other admins. Tenants are
+// only ever assigned by an admin.
+type CreateUserRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+	Role     string `json:"role" binding:"required,oneof=customer agent admin"`
+	Tenant   string `json:"tenant" binding:"omitempty,max=64"` // Optional, defaults to "default"
+}
+
+// UpdateUserRequest moves a user to another role or tenant (admins only);
+// omitted fields are unchanged. Takes effect at the user's next login.
+type UpdateUserRequest struct {
+	Role   string `json:"role" binding:"omitempty,oneof=customer agent admin"`
+	Tenant string `json:"tenant" binding:"omitempty,max=64"`
 }
 
 // MustParseUUID parses a UUID string, panics on error (for simplicity)
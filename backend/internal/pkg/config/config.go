@@ -0,0 +1,502 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+)
+
+// Config is the typed configuration shared by every service.
+// Values are layered: struct defaults, then the optional YAML file,
+// then environment variables, then command-line flags.
+//
+// Field tags:
+//
+//	yaml:"name"      key in the YAML file (and the flag name, dotted by section)
+//	env:"NAME"       environment variable
+//	default:"value"  fallback when nothing else sets the field
+//	required:"a,b"   services that refuse to start without the field
+//	secret:"true"    value is masked by Redacted/String
+type Config struct {
+	Service      string             `yaml:"-"`
+	HTTP         HTTPConfig         `yaml:"http"`
+	DB           DBConfig           `yaml:"db"`
+	Kafka        KafkaConfig        `yaml:"kafka"`
+	Redis        RedisConfig        `yaml:"redis"`
+	JWT          JWTConfig          `yaml:"jwt"`
+	Users        UsersConfig        `yaml:"users"`
+	AI           AIConfig           `yaml:"ai"`
+	Notification NotificationConfig `yaml:"notification"`
+	Storage      StorageConfig      `yaml:"storage"`
+	Tickets      TicketConfig       `yaml:"tickets"`
+}
+
+type HTTPConfig struct {
+	Port string `yaml:"port" env:"HTTP_PORT" required:"user,ticket,ai,notification"`
+}
+
+type DBConfig struct {
+	Host     string `yaml:"host" env:"DB_HOST" default:"postgres"`
+	Port     string `yaml:"port" env:"DB_PORT" default:"5432"`
+	Username string `yaml:"username" env:"DB_USERNAME" default:"ticket_user"`
+	Password string `yaml:"password" env:"DB_PASSWORD" required:"user,ticket,ai,ai-backfill,notification" secret:"true"`
+	Database string `yaml:"database" env:"DB_DATABASE" default:"Ticket"`
+	SSLMode  string `yaml:"sslmode" env:"DB_SSLMODE" default:"disable"`
+}
+
+// DSN builds the Postgres connection string
+func (c DBConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
+		c.Host, c.Username, c.Password, c.Database, c.Port, c.SSLMode)
+}
+
+type KafkaConfig struct {
+	Brokers []string `yaml:"brokers" env:"KAFKA_BROKER" default:"kafka:9092" required:"ticket,ai,ai-backfill,notification"`
+	Topic   string   `yaml:"topic" env:"KAFKA_TOPIC" default:"ticket-events" required:"ticket,ai,ai-backfill,notification"`
+}
+
+type RedisConfig struct {
+	Addr string `yaml:"addr" env:"REDIS_ADDR" default:"localhost:6379" required:"ticket,ai,ai-backfill,notification"`
+}
+
+type JWTConfig struct {
+	Secret string `yaml:"secret" env:"JWT_SECRET" required:"user,ticket,ai,notification" secret:"true"`
+}
+
+// UsersConfig seeds the first admin; further agents and admins are created
+// by an admin, since self-registration only makes customers
+type UsersConfig struct {
+	AdminEmail    string `yaml:"admin_email" env:"ADMIN_EMAIL"`
+	AdminPassword string `yaml:"admin_password" env:"ADMIN_PASSWORD" secret:"true"`
+}
+
+type AIConfig struct {
+	Provider             string  `yaml:"provider" env:"AI_PROVIDER" default:"gemini"`
+	Model                string  `yaml:"model" env:"AI_MODEL" default:"gemini-2.5-flash"`
+	GeminiAPIKey         string  `yaml:"gemini_api_key" env:"GEMINI_API_KEY" secret:"true"`                    // Required when provider is gemini
+	ReplayFile           string  `yaml:"replay_file" env:"AI_REPLAY_FILE"`                                     // Recorded responses for the replay provider
+	MaxPromptTokens      int     `yaml:"max_prompt_tokens" env:"AI_MAX_PROMPT_TOKENS" default:"6000"`          // Budget for ticket context in the prompt
+	HistoryTickets       int     `yaml:"history_tickets" env:"AI_HISTORY_TICKETS" default:"5"`                 // Customer's recent tickets to include
+	MaxAttachmentBytes   int     `yaml:"max_attachment_bytes" env:"AI_MAX_ATTACHMENT_BYTES" default:"2097152"` // Per attachment read for text extraction
+	AutoApplyThreshold   float64 `yaml:"auto_apply_threshold" env:"AI_AUTO_APPLY_THRESHOLD" default:"0.7"`     // Below this, results wait for agent review
+	AutoResolveThreshold float64 `yaml:"auto_resolve_threshold" env:"AI_AUTO_RESOLVE_THRESHOLD" default:"0.9"` // Answer customers directly in opted-in categories
+	InputCostPerMTok     float64 `yaml:"input_cost_per_mtok" env:"AI_INPUT_COST_PER_MTOK" default:"0.30"`      // USD per million prompt tokens
+	OutputCostPerMTok    float64 `yaml:"output_cost_per_mtok" env:"AI_OUTPUT_COST_PER_MTOK" default:"2.50"`    // USD per million output tokens
+	// Escalation rules raise the priority from sentiment and urgency, each as
+	// "conditions:action", e.g. "sentiment=angry:+1" or
+	// "sentiment=frustrated|angry&urgency>=0.7:high". Actions are "+N" levels
+	// or a priority name to raise to.
+	EscalationRules    []string `yaml:"escalation_rules" env:"AI_ESCALATION_RULES" default:"sentiment=angry:+1,urgency>=0.8:+1"`
+	AgentLanguage      string   `yaml:"agent_language" env:"AI_AGENT_LANGUAGE" default:"en"`            // ISO 639-1; tickets in other languages are translated for agents
+	SummaryMinComments int      `yaml:"summary_min_comments" env:"AI_SUMMARY_MIN_COMMENTS" default:"3"` // Threads this long get a rolling summary; POST .../summarize works on any ticket
+
+	// LLM call limits. When retries are exhausted or the circuit breaker is
+	// open, tickets are left in ai_queued and retried every BreakerCooldown.
+	RequestTimeout   time.Duration `yaml:"request_timeout" env:"AI_REQUEST_TIMEOUT" default:"60s"`
+	RateLimit        float64       `yaml:"rate_limit" env:"AI_RATE_LIMIT" default:"2"` // LLM requests per second
+	RateBurst        int           `yaml:"rate_burst" env:"AI_RATE_BURST" default:"5"`
+	MaxConcurrent    int           `yaml:"max_concurrent" env:"AI_MAX_CONCURRENT" default:"4"` // LLM requests in flight
+	MaxRetries       int           `yaml:"max_retries" env:"AI_MAX_RETRIES" default:"3"`
+	RetryBackoff     time.Duration `yaml:"retry_backoff" env:"AI_RETRY_BACKOFF" default:"1s"`          // Doubled per attempt unless the provider sends Retry-After
+	BreakerThreshold int           `yaml:"breaker_threshold" env:"AI_BREAKER_THRESHOLD" default:"5"`   // Consecutive failed calls that open the circuit
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown" env:"AI_BREAKER_COOLDOWN" default:"1m"`    // Open circuit wait before a trial call
+	DailyTokenBudget int           `yaml:"daily_token_budget" env:"AI_DAILY_TOKEN_BUDGET" default:"0"` // Per tenant per UTC day; 0 is unlimited
+	DailyCostBudget  float64       `yaml:"daily_cost_budget" env:"AI_DAILY_COST_BUDGET" default:"0"`   // USD per tenant per UTC day; 0 is unlimited
+	TenantBudgets    []string      `yaml:"tenant_budgets" env:"AI_TENANT_BUDGETS"`                     // Overrides as "tenant:tokens:cost", e.g. "acme:5000000:20"
+
+	// PII and secrets are masked before ticket text reaches the LLM. Kinds:
+	// card, iban, email, phone, api_key, password.
+	Redact          []string `yaml:"redact" env:"AI_REDACT" default:"card,iban,email,phone,api_key,password"`
+	TenantRedaction []string `yaml:"tenant_redaction" env:"AI_TENANT_REDACTION"` // Overrides as "tenant:kind|kind", or "tenant:none" to send text as-is
+
+	// Consumer worker pool. Events for one ticket always go to the same
+	// worker, so they are handled in order.
+	Workers     int `yaml:"workers" env:"AI_WORKERS" default:"4"`
+	WorkerQueue int `yaml:"worker_queue" env:"AI_WORKER_QUEUE" default:"16"` // Per worker; fetching pauses while a queue is full
+
+	// Deduplication in Redis. Unchanged prompts reuse the cached result and
+	// redelivered events are skipped; both degrade to normal processing
+	// when Redis is down.
+	ResultCacheTTL time.Duration `yaml:"result_cache_ttl" env:"AI_RESULT_CACHE_TTL" default:"24h"` // Classification results by prompt hash
+	EventDedupTTL  time.Duration `yaml:"event_dedup_ttl" env:"AI_EVENT_DEDUP_TTL" default:"72h"`   // How long handled event ids are remembered
+
+	EmbeddingProvider  string        `yaml:"embedding_provider" env:"AI_EMBEDDING_PROVIDER" default:"hashing"` // hashing (local) or gemini
+	EmbeddingModel     string        `yaml:"embedding_model" env:"AI_EMBEDDING_MODEL" default:"text-embedding-004"`
+	DuplicateThreshold float64       `yaml:"duplicate_threshold" env:"AI_DUPLICATE_THRESHOLD" default:"0.8"` // Cosine similarity to flag a duplicate; tuned for hashing, raise for gemini (~0.9)
+	RelatedThreshold   float64       `yaml:"related_threshold" env:"AI_RELATED_THRESHOLD" default:"0.4"`     // Raise for gemini (~0.75)
+	SimilarLimit       int           `yaml:"similar_limit" env:"AI_SIMILAR_LIMIT" default:"5"`
+	KBArticles         int           `yaml:"kb_articles" env:"AI_KB_ARTICLES" default:"3"`                // Knowledge base articles retrieved per ticket
+	KBMinScore         float64       `yaml:"kb_min_score" env:"AI_KB_MIN_SCORE" default:"0.15"`           // Minimum similarity for an article to be offered
+	SimilarityWindow   time.Duration `yaml:"similarity_window" env:"AI_SIMILARITY_WINDOW" default:"720h"` // How far back to look for matches
+}
+
+// Cost is the USD price of a call with the given token usage
+func (c AIConfig) Cost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*c.InputCostPerMTok + float64(outputTokens)*c.OutputCostPerMTok) / 1e6
+}
+
+// Budget is the daily token and USD limit for tenant, from TenantBudgets or
+// the defaults; 0 means unlimited
+func (c AIConfig) Budget(tenant string) (tokens int, cost float64) {
+	for _, b := range c.TenantBudgets {
+		if t, tok, usd, err := parseTenantBudget(b); err == nil && t == tenant {
+			return tok, usd
+		}
+	}
+	return c.DailyTokenBudget, c.DailyCostBudget
+}
+
+// parseTenantBudget reads "tenant:tokens:cost"
+func parseTenantBudget(s string) (tenant string, tokens int, cost float64, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", 0, 0, fmt.Errorf("invalid tenant budget %q: want tenant:tokens:cost", s)
+	}
+	if tokens, err = strconv.Atoi(parts[1]); err != nil || tokens < 0 {
+		return "", 0, 0, fmt.Errorf("invalid token budget in %q", s)
+	}
+	if cost, err = strconv.ParseFloat(parts[2], 64); err != nil || cost < 0 {
+		return "", 0, 0, fmt.Errorf("invalid cost budget in %q", s)
+	}
+	return parts[0], tokens, cost, nil
+}
+
+// Redaction is the kinds of data masked for tenant, from TenantRedaction or
+// the defaults
+func (c AIConfig) Redaction(tenant string) []string {
+	for _, r := range c.TenantRedaction {
+		if t, kinds, err := parseTenantRedaction(r); err == nil && t == tenant {
+			return kinds
+		}
+	}
+	return c.Redact
+}
+
+// parseTenantRedaction reads "tenant:kind|kind" or "tenant:none"
+func parseTenantRedaction(s string) (tenant string, kinds []string, err error) {
+	tenant, list, ok := strings.Cut(s, ":")
+	if !ok || tenant == "" || list == "" {
+		return "", nil, fmt.Errorf("invalid tenant redaction %q: want tenant:kind|kind or tenant:none", s)
+	}
+	if list == "none" {
+		return tenant, []string{}, nil
+	}
+	return tenant, strings.Split(list, "|"), nil
+}
+
+// RedactionKinds are the kinds of data services/ai/redact can detect
+var RedactionKinds = []string{"api_key", "password", "email", "iban", "card", "phone"}
+
+// checkRedactionKinds rejects kinds with no detector, so a typo can't
+// quietly send that data to the LLM
+func checkRedactionKinds(env string, kinds []string) error {
+	for _, k := range kinds {
+		k = strings.TrimSpace(k)
+		if k != "" && !slices.Contains(RedactionKinds, k) {
+			return fmt.Errorf("unknown redaction kind %q in %s (known: %s)", k, env, strings.Join(RedactionKinds, ", "))
+		}
+	}
+	return nil
+}
+
+// NotificationConfig enables the delivery channels: each is used only when
+// configured, except the in-app inbox
+type NotificationConfig struct {
+	SlackWebhookURL string `yaml:"slack_webhook_url" env:"SLACK_WEBHOOK_URL" secret:"true"`
+	TeamsWebhookURL string `yaml:"teams_webhook_url" env:"TEAMS_WEBHOOK_URL" secret:"true"`        // Teams Workflows "post to a channel when a webhook request is received"
+	WebhookURL      string `yaml:"webhook_url" env:"NOTIFICATION_WEBHOOK_URL"`                     // Receives every ticket event as published
+	WebhookSecret   string `yaml:"webhook_secret" env:"NOTIFICATION_WEBHOOK_SECRET" secret:"true"` // Signs webhook bodies (X-Signature-256)
+	EmailSender     string `yaml:"email_sender" env:"EMAIL_SENDER"`
+	EmailPassword   string `yaml:"email_password" env:"EMAIL_PASSWORD" secret:"true"`
+	EmailReceiver   string `yaml:"email_receiver" env:"EMAIL_RECEIVER"`
+	SMTPHost        string `yaml:"smtp_host" env:"SMTP_HOST" default:"smtp.gmail.com"`
+	SMTPPort        string `yaml:"smtp_port" env:"SMTP_PORT" default:"587"`
+
+	// Every channel gets its own queue, retries and delivery record
+	DeliveryRetries       int           `yaml:"delivery_retries" env:"NOTIFICATION_DELIVERY_RETRIES" default:"3"`
+	DeliveryBackoff       time.Duration `yaml:"delivery_backoff" env:"NOTIFICATION_DELIVERY_BACKOFF" default:"2s"`               // Doubled per attempt
+	DeliveryTimeout       time.Duration `yaml:"delivery_timeout" env:"NOTIFICATION_DELIVERY_TIMEOUT" default:"10s"`              // Per attempt
+	DeliveryQueue         int           `yaml:"delivery_queue" env:"NOTIFICATION_DELIVERY_QUEUE" default:"1000"`                 // Per channel; overflow waits for the retry sweep
+	DeliveryRetryInterval time.Duration `yaml:"delivery_retry_interval" env:"NOTIFICATION_DELIVERY_RETRY_INTERVAL" default:"5m"` // How often failed deliveries are retried
+	DeliveryMaxAttempts   int           `yaml:"delivery_max_attempts" env:"NOTIFICATION_DELIVERY_MAX_ATTEMPTS" default:"20"`     // Across all retries, then a delivery stays failed
+
+	// In-app inbox
+	UnreadCacheTTL time.Duration `yaml:"unread_cache_ttl" env:"NOTIFICATION_UNREAD_CACHE_TTL" default:"10m"` // Unread counts in Redis; cleared on every change
+	InboxPageSize  int           `yaml:"inbox_page_size" env:"NOTIFICATION_INBOX_PAGE_SIZE" default:"50"`    // Most notifications a list request returns
+}
+
+// TicketConfig controls the ticket lifecycle
+type TicketConfig struct {
+	AutoCloseAfter time.Duration `yaml:"auto_close_after" env:"TICKET_AUTO_CLOSE_AFTER" default:"72h"` // Auto-resolved tickets close if the customer stays silent this long
+
+	// Realtime updates streamed to clients over SSE
+	StreamHistory   int           `yaml:"stream_history" env:"TICKET_STREAM_HISTORY" default:"1000"`    // Recent updates kept for clients resuming with Last-Event-ID
+	StreamHeartbeat time.Duration `yaml:"stream_heartbeat" env:"TICKET_STREAM_HEARTBEAT" default:"25s"` // Keeps idle connections open through proxies
+}
+
+// StorageConfig selects the attachment blob store and upload limits
+type StorageConfig struct {
+	Backend        string        `yaml:"backend" env:"STORAGE_BACKEND" default:"local"` // "local" or "s3"
+	LocalDir       string        `yaml:"local_dir" env:"STORAGE_LOCAL_DIR" default:"./data/attachments"`
+	S3Endpoint     string        `yaml:"s3_endpoint" env:"S3_ENDPOINT"` // e.g. "minio:9000"
+	S3Region       string        `yaml:"s3_region" env:"S3_REGION" default:"us-east-1"`
+	S3Bucket       string        `yaml:"s3_bucket" env:"S3_BUCKET"`
+	S3AccessKey    string        `yaml:"s3_access_key" env:"S3_ACCESS_KEY" secret:"true"`
+	S3SecretKey    string        `yaml:"s3_secret_key" env:"S3_SECRET_KEY" secret:"true"`
+	S3UseSSL       bool          `yaml:"s3_use_ssl" env:"S3_USE_SSL" default:"true"`
+	MaxUploadBytes int           `yaml:"max_upload_bytes" env:"ATTACHMENT_MAX_BYTES" default:"10485760"`
+	AllowedTypes   []string      `yaml:"allowed_types" env:"ATTACHMENT_ALLOWED_TYPES" default:"image/png,image/jpeg,image/gif,image/webp,text/plain,application/pdf,application/json,application/zip"`
+	SigningKey     string        `yaml:"signing_key" env:"ATTACHMENT_SIGNING_KEY" secret:"true"` // Falls back to the JWT secret
+	URLTTL         time.Duration `yaml:"url_ttl" env:"ATTACHMENT_URL_TTL" default:"15m"`
+}
+
+// defaultPorts keeps the historical listen port of each HTTP service
+var defaultPorts = map[string]string{
+	"user":         "8080",
+	"ticket":       "8081",
+	"ai":           "8082",
+	"notification": "8083",
+}
+
+// Load builds the config for the named service from defaults, the YAML file
+// given by -config or CONFIG_FILE, the environment and the remaining flags.
+func Load(service string, args []string) (*Config, error) {
+	_ = godotenv.Load() // Ignores errors if no .env
+
+	cfg := &Config{Service: service}
+	if err := walk(cfg, func(f field) error {
+		if def := f.tag.Get("default"); def != "" {
+			return f.set(def)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if port, ok := defaultPorts[service]; ok {
+		cfg.HTTP.Port = port
+	}
+
+	fs := flag.NewFlagSet(service, flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	flagValues := map[string]*string{}
+	if err := walk(cfg, func(f field) error {
+		flagValues[f.path] = fs.String(f.path, "", "overrides "+f.tag.Get("env"))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.UnmarshalWithOptions(data, cfg, yaml.Strict()); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", *configFile, err)
+		}
+	}
+
+	if err := walk(cfg, func(f field) error {
+		if v, ok := os.LookupEnv(f.tag.Get("env")); ok && v != "" {
+			if err := f.set(v); err != nil {
+				return fmt.Errorf("%s: %w", f.tag.Get("env"), err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+	if err := walk(cfg, func(f field) error {
+		if set[f.path] {
+			if err := f.set(*flagValues[f.path]); err != nil {
+				return fmt.Errorf("-%s: %w", f.path, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate reports every field the current service requires but is empty
+func (c *Config) Validate() error {
+	var missing []string
+	_ = walk(c, func(f field) error {
+		for _, svc := range strings.Split(f.tag.Get("required"), ",") {
+			if svc == c.Service && f.value.IsZero() {
+				missing = append(missing, fmt.Sprintf("%s (%s)", f.tag.Get("env"), f.path))
+			}
+		}
+		return nil
+	})
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required config for %s service: %s", c.Service, strings.Join(missing, ", "))
+	}
+
+	if c.Service == "user" && c.Users.AdminEmail != "" && len(c.Users.AdminPassword) < 6 {
+		return fmt.Errorf("ADMIN_EMAIL requires an ADMIN_PASSWORD of at least 6 characters")
+	}
+
+	if c.Service == "ai" || c.Service == "ai-eval" || c.Service == "ai-backfill" {
+		switch {
+		case c.AI.Provider == "gemini" && c.AI.GeminiAPIKey == "":
+			return fmt.Errorf("missing required config for %s service: GEMINI_API_KEY (ai.gemini_api_key)", c.Service)
+		case c.AI.Provider == "replay" && c.AI.ReplayFile == "":
+			return fmt.Errorf("provider replay requires AI_REPLAY_FILE")
+		}
+		for _, b := range c.AI.TenantBudgets {
+			if _, _, _, err := parseTenantBudget(b); err != nil {
+				return err
+			}
+		}
+		if err := checkRedactionKinds("AI_REDACT", c.AI.Redact); err != nil {
+			return err
+		}
+		for _, r := range c.AI.TenantRedaction {
+			_, kinds, err := parseTenantRedaction(r)
+			if err != nil {
+				return err
+			}
+			if err := checkRedactionKinds("AI_TENANT_REDACTION", kinds); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.Service == "ticket" || c.Service == "ai" || c.Service == "ai-backfill" {
+		switch c.Storage.Backend {
+		case "local":
+		case "s3":
+			if c.Storage.S3Endpoint == "" || c.Storage.S3Bucket == "" {
+				return fmt.Errorf("storage backend s3 requires S3_ENDPOINT and S3_BUCKET")
+			}
+		default:
+			return fmt.Errorf("unknown storage backend %q", c.Storage.Backend)
+		}
+	}
+	return nil
+}
+
+// Redacted returns a copy with every secret field masked
+func (c *Config) Redacted() Config {
+	out := *c
+	out.Kafka.Brokers = append([]string(nil), c.Kafka.Brokers...)
+	_ = walk(&out, func(f field) error {
+		if f.tag.Get("secret") == "true" && !f.value.IsZero() {
+			f.value.SetString("****")
+		}
+		return nil
+	})
+	return out
+}
+
+// String renders the redacted config as YAML, safe for logs
+func (c *Config) String() string {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("config: %v", err)
+	}
+	return string(data)
+}
+
+type field struct {
+	path  string
+	tag   reflect.StructTag
+	value reflect.Value
+}
+
+// set parses s into the field according to its kind
+func (f field) set(s string) error {
+	switch f.value.Interface().(type) {
+	case string:
+		f.value.SetString(s)
+	case []string:
+		var parts []string
+		for _, p := range strings.Split(s, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				parts = append(parts, p)
+			}
+		}
+		f.value.Set(reflect.ValueOf(parts))
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(n))
+	case float64:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		f.value.SetFloat(n)
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(b)
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetInt(int64(d))
+	default:
+		return errors.New("unsupported config field type " + f.value.Type().String())
+	}
+	return nil
+}
+
+// walk calls fn for every leaf field that carries an env tag
+func walk(cfg *Config, fn func(field) error) error {
+	return walkStruct(reflect.ValueOf(cfg).Elem(), "", fn)
+}
+
+func walkStruct(v reflect.Value, prefix string, fn func(field) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if sf.Type.Kind() == reflect.Struct {
+			if err := walkStruct(v.Field(i), path, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if sf.Tag.Get("env") == "" {
+			continue
+		}
+		if err := fn(field{path: path, tag: sf.Tag, value: v.Field(i)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv unsets every variable Load reads, for the duration of the test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	_ = walk(&Config{}, func(f field) error {
+		t.Setenv(f.tag.Get("env"), "")
+		return nil
+	})
+}
+
+func writeFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := "db:\n  host: from-file\n  password: file-secret\njwt:\n  secret: file-jwt\nai:\n  request_timeout: 5s\n"
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    []string
+		file    bool
+		host    string
+		port    string
+		brokers []string
+		wantTO  time.Duration
+	}{
+		{
+			name:    "defaults",
+			env:     map[string]string{"DB_PASSWORD": "x", "JWT_SECRET": "y"},
+			host:    "postgres",
+			port:    "8080",
+			brokers: []string{"kafka:9092"},
+			wantTO:  60 * time.Second,
+		},
+		{
+			name:    "file over defaults",
+			file:    true,
+			host:    "from-file",
+			port:    "8080",
+			brokers: []string{"kafka:9092"},
+			wantTO:  5 * time.Second,
+		},
+		{
+			name:    "env over file",
+			file:    true,
+			env:     map[string]string{"DB_HOST": "from-env", "KAFKA_BROKER": "a:1, b:2", "HTTP_PORT": "9000"},
+			host:    "from-env",
+			port:    "9000",
+			brokers: []string{"a:1", "b:2"},
+			wantTO:  5 * time.Second,
+		},
+		{
+			name:    "flags over env",
+			file:    true,
+			env:     map[string]string{"DB_HOST": "from-env", "AI_REQUEST_TIMEOUT": "10s"},
+			args:    []string{"-db.host", "from-flag", "-ai.request_timeout", "2s"},
+			host:    "from-flag",
+			port:    "8080",
+			brokers: []string{"kafka:9092"},
+			wantTO:  2 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := tt.args
+			if tt.file {
+				args = append([]string{"-config", writeFile(t, file)}, args...)
+			}
+			cfg, err := Load("user", args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.DB.Host != tt.host {
+				t.Errorf("DB.Host = %q, want %q", cfg.DB.Host, tt.host)
+			}
+			if cfg.HTTP.Port != tt.port {
+				t.Errorf("HTTP.Port = %q, want %q", cfg.HTTP.Port, tt.port)
+			}
+			if strings.Join(cfg.Kafka.Brokers, ",") != strings.Join(tt.brokers, ",") {
+				t.Errorf("Kafka.Brokers = %q, want %q", cfg.Kafka.Brokers, tt.brokers)
+			}
+			if cfg.AI.RequestTimeout != tt.wantTO {
+				t.Errorf("AI.RequestTimeout = %s, want %s", cfg.AI.RequestTimeout, tt.wantTO)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		env     map[string]string
+		args    []string
+		file    string
+		wantErr string
+	}{
+		{"missing required", "user", nil, nil, "", "missing required config for user service: DB_PASSWORD (db.password), JWT_SECRET (jwt.secret)"},
+		{"required per service", "ai-eval", nil, nil, "", "GEMINI_API_KEY"},
+		{"bad env value", "user", map[string]string{"AI_REQUEST_TIMEOUT": "soon"}, nil, "", "AI_REQUEST_TIMEOUT"},
+		{"bad flag value", "user", nil, []string{"-ai.workers", "many"}, "", "-ai.workers"},
+		{"unknown flag", "user", nil, []string{"-db.hots", "x"}, "", "flag provided but not defined"},
+		{"unknown file key", "user", nil, nil, "db:\n  hots: x\n", "failed to parse config file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, tt.file)}, args...)
+			}
+			_, err := Load(tt.service, args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	base := func(service string) *Config {
+		cfg := &Config{Service: service}
+		_ = walk(cfg, func(f field) error {
+			if def := f.tag.Get("default"); def != "" {
+				return f.set(def)
+			}
+			return nil
+		})
+		cfg.HTTP.Port = "8080"
+		cfg.DB.Password = "x"
+		cfg.JWT.Secret = "y"
+		cfg.AI.GeminiAPIKey = "key"
+		return cfg
+	}
+	tests := []struct {
+		name    string
+		service string
+		change  func(c *Config)
+		wantErr string // Empty when valid
+	}{
+		{"valid user", "user", func(c *Config) {}, ""},
+		{"valid ai", "ai", func(c *Config) {}, ""},
+		{"required field of another service", "ai-eval", func(c *Config) { c.JWT.Secret = "" }, ""},
+		{"admin without password", "user", func(c *Config) { c.Users.AdminEmail = "a@example.com" }, "ADMIN_PASSWORD"},
+		{"admin with password", "user", func(c *Config) { c.Users.AdminEmail, c.Users.AdminPassword = "a@example.com", "secret1" }, ""},
+		{"gemini without key", "ai", func(c *Config) { c.AI.GeminiAPIKey = "" }, "GEMINI_API_KEY"},
+		{"replay without file", "ai-eval", func(c *Config) { c.AI.Provider = "replay" }, "AI_REPLAY_FILE"},
+		{"bad tenant budget", "ai", func(c *Config) { c.AI.TenantBudgets = []string{"acme:lots:1"} }, "acme:lots:1"},
+		{"bad tenant redaction", "ai", func(c *Config) { c.AI.TenantRedaction = []string{"acme"} }, "invalid tenant redaction"},
+		{"unknown redaction kind", "ai", func(c *Config) { c.AI.Redact = []string{"email", "ssn"} }, `unknown redaction kind "ssn" in AI_REDACT`},
+		{"unknown tenant redaction kind", "ai", func(c *Config) { c.AI.TenantRedaction = []string{"acme:email|mail"} }, `unknown redaction kind "mail" in AI_TENANT_REDACTION`},
+		{"tenant redaction off", "ai", func(c *Config) { c.AI.TenantRedaction = []string{"acme:none"} }, ""},
+		{"s3 without bucket", "ticket", func(c *Config) { c.Storage.Backend, c.Storage.S3Endpoint = "s3", "minio:9000" }, "S3_BUCKET"},
+		{"unknown storage", "ticket", func(c *Config) { c.Storage.Backend = "ftp" }, `unknown storage backend "ftp"`},
+		{"storage unused", "user", func(c *Config) { c.Storage.Backend = "ftp" }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base(tt.service)
+			tt.change(cfg)
+			err := cfg.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate = %v, want nil", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := &Config{DB: DBConfig{Host: "postgres", Password: "hunter2"}, Kafka: KafkaConfig{Brokers: []string{"kafka:9092"}}}
+	out := cfg.Redacted()
+	if out.DB.Password != "****" || out.DB.Host != "postgres" {
+		t.Errorf("Redacted DB = %+v, want the password masked and the host kept", out.DB)
+	}
+	if out.JWT.Secret != "" {
+		t.Errorf("Redacted JWT.Secret = %q, want empty secrets left empty", out.JWT.Secret)
+	}
+	if cfg.DB.Password != "hunter2" {
+		t.Error("Redacted changed the original config")
+	}
+	if strings.Contains(cfg.String(), "hunter2") {
+		t.Error("String leaks the DB password")
+	}
+}
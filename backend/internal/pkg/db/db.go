@@ -33,8 +33,66 @@ func New(dsn string) (*DB, error) {
 }
 
 func (db *DB) Migrate() error {
-	if err := db.AutoMigrate(&models.User{}, &models.Ticket{}); err != nil {
+	if err := db.AutoMigrate(&models.User{}, &models.Ticket{}, &models.Attachment{}, &models.Comment{},
+		&models.Category{}, &models.Priority{}, &models.AIClassification{}, &models.ClassificationFeedback{},
+		&models.PromptTemplate{}, &models.TicketEmbedding{}, &models.TicketSimilarity{},
+		&models.Article{}, &models.TicketSummary{}, &models.AIUsage{}, &models.ReplyDraft{},
+		&models.CopilotConversation{}, &models.CopilotMessage{}, &models.Notification{},
+		&models.NotificationDelivery{}); err != nil {
 		return fmt.Errorf("failed to migrate: %w", err)
 	}
+	if err := db.supersedeStaleRuns(); err != nil {
+		return err
+	}
+	if err := db.backfillPendingCustomerSince(); err != nil {
+		return err
+	}
+	return db.seedTaxonomy()
+}
+
+// supersedeStaleRuns takes pending runs that a newer run replaced, from
+// before runs were superseded on creation, out of the review queue
+func (db *DB) supersedeStaleRuns() error {
+	err := db.Exec(`UPDATE ai_classifications c SET status = ? WHERE status = ? AND reviewed_at IS NULL
+		AND EXISTS (SELECT 1 FROM ai_classifications n WHERE n.ticket_id = c.ticket_id AND n.created_at > c.created_at)`,
+		models.ClassificationSuperseded, models.ClassificationPending).Error
+	if err != nil {
+		return fmt.Errorf("failed to supersede stale classifications: %w", err)
+	}
+	return nil
+}
+
+// backfillPendingCustomerSince dates tickets that were already waiting for
+// the customer before the column existed, so auto-close still sees them
+func (db *DB) backfillPendingCustomerSince() error {
+	err := db.Exec(`UPDATE tickets SET pending_customer_since = COALESCE(auto_resolved_at, updated_at)
+		WHERE status = ? AND pending_customer_since IS NULL`, "pending_customer").Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill pending_customer_since: %w", err)
+	}
+	return nil
+}
+
+// seedTaxonomy inserts the default categories and priorities into empty tables
+func (db *DB) seedTaxonomy() error {
+	var count int64
+	if err := db.Model(&models.Category{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		categories := append([]models.Category(nil), models.DefaultCategories...)
+		if err := db.Create(&categories).Error; err != nil {
+			return fmt.Errorf("failed to seed categories: %w", err)
+		}
+	}
+	if err := db.Model(&models.Priority{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		priorities := append([]models.Priority(nil), models.DefaultPriorities...)
+		if err := db.Create(&priorities).Error; err != nil {
+			return fmt.Errorf("failed to seed priorities: %w", err)
+		}
+	}
 	return nil
 }
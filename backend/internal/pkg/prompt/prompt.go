@@ -0,0 +1,91 @@
+// Package prompt parses and renders the versioned LLM prompt templates.
+// Templates use text/template syntax, e.g. {{.Ticket}}; the variables each
+// prompt may use are listed in Variables.
+package prompt
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var defaults embed.FS
+
+// Versions are the version numbers of the embedded templates. Bump a
+// template's number when its file changes, and only that one; versions
+// stored in the DB are numbered above it.
+var Versions = map[string]int{
+	"classify":  5,
+	"summarize": 5,
+	"reply":     5,
+	"copilot":   5,
+}
+
+// Variables lists the values passed to each named prompt
+var Variables = map[string][]string{
+	"classify":  {"Taxonomy", "Ticket", "Articles", "Tenant"},
+	"summarize": {"Ticket", "Previous", "Language"},
+	"reply":     {"Ticket", "Articles", "Tone", "Language", "Instructions"},
+	"copilot":   {"Ticket", "Conversation", "Language"},
+}
+
+// Default returns the embedded template for name
+func Default(name string) (string, bool) {
+	body, err := defaults.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		return "", false
+	}
+	return string(body), true
+}
+
+// DefaultLabel is the version string recorded on AI runs that used the
+// embedded template, e.g. "classify-v5"
+func DefaultLabel(name string) string {
+	return Label(name, Versions[name], "")
+}
+
+// Label is the version string recorded on AI runs, e.g. "classify-v7" or
+// "classify-v6@acme" for a tenant override
+func Label(name string, version int, tenant string) string {
+	label := fmt.Sprintf("%s-v%d", name, version)
+	if tenant != "" {
+		label += "@" + tenant
+	}
+	return label
+}
+
+// Parse compiles a template; referencing an unknown variable fails at render time
+func Parse(name, body string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(body)
+}
+
+// Validate checks that body parses and only uses the variables of name
+func Validate(name, body string) error {
+	vars, ok := Variables[name]
+	if !ok {
+		return fmt.Errorf("unknown prompt %q", name)
+	}
+	t, err := Parse(name, body)
+	if err != nil {
+		return err
+	}
+	sample := map[string]string{}
+	for _, v := range vars {
+		sample[v] = v
+	}
+	if _, err := Render(t, sample); err != nil {
+		return fmt.Errorf("%w (available variables: %s)", err, strings.Join(vars, ", "))
+	}
+	return nil
+}
+
+// Render executes t with vars
+func Render(t *template.Template, vars map[string]string) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, vars); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
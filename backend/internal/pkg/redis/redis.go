@@ -3,10 +3,11 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -14,11 +15,7 @@ type Client struct {
 	*redis.Client
 }
 
-func New() *Client {
-	addr := os.Getenv("REDIS_ADDR")
-	if addr == "" {
-		addr = "localhost:6379"
-	}
+func New(addr string) *Client {
 	rdb := redis.NewClient(&redis.Options{
 		Addr: addr,
 	})
@@ -50,3 +47,46 @@ func (c *Client) CacheGet(ctx context.Context, key string, v interface{}) error
 func (c *Client) CacheDel(ctx context.Context, key string) error {
 	return c.Del(ctx, key).Err()
 }
+
+// CounterGet returns the integer at key, 0 if it is not set
+func (c *Client) CounterGet(ctx context.Context, key string) (int64, error) {
+	n, err := c.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// CounterIncr increments the integer at key and keeps it for ttl (0 keeps it)
+func (c *Client) CounterIncr(ctx context.Context, key string, ttl time.Duration) error {
+	pipe := c.TxPipeline()
+	pipe.Incr(ctx, key)
+	if ttl > 0 {
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ErrLocked is returned by Lock while someone else holds the key
+var ErrLocked = errors.New("locked")
+
+// unlockScript deletes the lock only if it still holds our token, so a
+// holder whose TTL ran out can't release the next holder's lock
+var unlockScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`)
+
+// Lock takes key for ttl, or returns ErrLocked if it is taken. The returned
+// function releases it.
+func (c *Client) Lock(ctx context.Context, key string, ttl time.Duration) (func(), error) {
+	token := uuid.NewString()
+	ok, err := c.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLocked
+	}
+	return func() {
+		unlockScript.Run(context.Background(), c.Client, []string{key}, token)
+	}, nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// bucketAttempts and bucketBackoff give MinIO, which often starts alongside
+// the services, about a minute to come up; the wait doubles per attempt
+const (
+	bucketAttempts = 7
+	bucketBackoff  = time.Second
+	bucketTimeout  = 10 * time.Second // Per attempt
+)
+
+// ErrNotFound is returned by Get when the key does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores attachment bytes under opaque keys
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New builds the blob store selected by cfg.Backend
+func New(cfg config.StorageConfig) (BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		// Not returned directly: a nil *LocalStore would be a non-nil BlobStore
+		store, err := NewLocalStore(cfg.LocalDir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "s3":
+		store := NewS3Store(cfg)
+		if err := ensureBucket(store); err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// ensureBucket retries EnsureBucket with exponential backoff
+func ensureBucket(store *S3Store) error {
+	wait := bucketBackoff
+	var err error
+	for attempt := 1; attempt <= bucketAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), bucketTimeout)
+		err = store.EnsureBucket(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if attempt < bucketAttempts {
+			log.Printf("Storage not ready (attempt %d/%d), retrying in %s: %v", attempt, bucketAttempts, wait, err)
+			time.Sleep(wait)
+			wait *= 2
+		}
+	}
+	return err
+}
@@ -2,36 +2,59 @@ package ai
 
 import (
 	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	promptpkg "ai-ticketing-backend/internal/pkg/prompt"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"ai-ticketing-backend/internal/pkg/storage"
+	"ai-ticketing-backend/services/ai/embedding"
+	"ai-ticketing-backend/services/ai/llm"
 	"ai-ticketing-backend/services/ai/repository"
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"os"
-	"strings" // New: For trim
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
 )
 
 type AIService interface {
 	ProcessTicketEvent(event *models.TicketCreatedEvent) error
 	ProcessTicketContent(ticketID uuid.UUID, title, description string) error
+	Translate(caller Caller, ticketID uuid.UUID, text, language string) (*models.TranslateResponse, error)
+	Summarize(caller Caller, ticketID uuid.UUID) (*models.TicketSummary, error)
+	RefreshSummary(ticketID uuid.UUID) error
+	Reclassify(caller Caller, ticketID uuid.UUID) (*models.AIClassification, error) // Runs even when nothing changed since the last classification
+	Backfill(ticketID uuid.UUID) (*models.AIClassification, error)                  // Reclassify for ai-backfill, which has no caller
+	ProcessQueued(limit int) (int, error)                                           // Retries tickets left in ai_queued while the LLM was unavailable
+	QueueTicket(ticketID uuid.UUID) error                                           // Leaves a ticket the consumer kept failing on to ProcessQueued
+	// DraftReply streams a reply draft to fn as it is written
+	DraftReply(caller Caller, ticketID uuid.UUID, req *models.DraftReplyRequest, fn func(chunk string) error) (*models.ReplyDraft, error)
+	// Copilot answers an agent's question about a ticket, streaming tool calls and the answer to emit
+	Copilot(caller Caller, ticketID uuid.UUID, req *models.CopilotRequest, emit func(event string, data interface{}) error) (*models.CopilotMessage, error)
+	CopilotConversation(caller Caller, ticketID, conversationID uuid.UUID) (*models.CopilotConversation, error)
 }
 
 type aiService struct {
-	repo   repository.TicketRepository
-	apiKey string
+	repo     repository.TicketRepository
+	store    storage.BlobStore // Attachment bytes for context; may be nil
+	provider llm.Provider
+	cfg      config.AIConfig
+	embedder embedding.Embedder // Duplicate detection; may be nil
+	producer *kafka.Writer      // Status change events; may be nil
+	cache    *redis.Client      // Results by prompt hash and per-ticket locks; may be nil
+	taxonomy taxonomyCache
+	prompts  promptCache
+	index    vectorIndex
+	articles articleIndex
+	rules    []escalationRule
+	usage    usageTracker
+	redactor redactors
 }
 
-func NewAIService(repo repository.TicketRepository) AIService {
-	key := os.Getenv("GEMINI_API_KEY")
-	log.Printf("GEMINI_API_KEY loaded: %s", key)
-	if key == "" {
-		panic("GEMINI_API_KEY not set")
-	}
-	return &aiService{repo: repo, apiKey: key}
+func NewAIService(repo repository.TicketRepository, store storage.BlobStore, provider llm.Provider, embedder embedding.Embedder, producer *kafka.Writer, cache *redis.Client, cfg config.AIConfig) AIService {
+	return &aiService{repo: repo, store: store, provider: provider, embedder: embedder, producer: producer, cache: cache, cfg: cfg, rules: parseEscalationRules(cfg.EscalationRules)}
 }
 
 func (s *aiService) ProcessTicketEvent(event *models.TicketCreatedEvent) error {
@@ -41,142 +64,254 @@ func (s *aiService) ProcessTicketEvent(event *models.TicketCreatedEvent) error {
 
 func (s *aiService) ProcessTicketContent(ticketID uuid.UUID, title, description string) error {
 	log.Printf("ProcessTicketContent called for ticket ID: %s", ticketID)
-	// Shorter prompt
-	prompt := fmt.Sprintf(`Classify ticket: %s. Description: %s. JSON only: {"category": "Billing|Bug|Feature|Support", "priority": "low|medium|high", "suggestion": "1-2 sentence reply"}`, title, description)
-
-	payload := map[string]interface{}{
-		"contents": []map[string]interface{}{
-			{
-				"parts": []map[string]interface{}{
-					{"text": prompt},
-				},
-			},
-		},
-		"generationConfig": map[string]interface{}{
-			"temperature":     0.1,
-			"maxOutputTokens": 1000,
-		},
-	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-
-	url := "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.5-flash:generateContent?key=" + s.apiKey
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	unlock, err := s.lockTicket(ticketID)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	log.Printf("Making Gemini API call for ticket ID: %s", ticketID)
-	resp, err := client.Do(req)
+	defer unlock()
+	ticket, err := s.repo.GetByID(ticketID)
 	if err != nil {
-		return fmt.Errorf("Gemini API call failed: %w", err)
+		return fmt.Errorf("ticket not found: %w", err)
 	}
-	defer resp.Body.Close()
+	_, err = s.classifyTicket(ticket, title, description, false)
+	return err
+}
 
-	log.Printf("Received Gemini API response for ticket ID: %s, Status: %d", ticketID, resp.StatusCode)
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Gemini error %d: %s", resp.StatusCode, string(body))
+// classifyTicket runs the classification pipeline and applies the result.
+// Unless forced, it skips tickets whose prompt is unchanged since their last
+// classification and reuses cached results; it returns nil when skipped.
+func (s *aiService) classifyTicket(ticket *models.Ticket, title, description string, force bool) (*models.AIClassification, error) {
+	ticketID := ticket.ID
+	oldStatus, oldPriority := ticket.Status, ticket.Priority
+	// Outside this service a claimed ticket is still queued
+	reportedStatus := oldStatus
+	if reportedStatus == StatusProcessing {
+		reportedStatus = StatusQueued
 	}
+	// Personal data is masked before it reaches the LLM or the embedder
+	redaction := s.redaction(ticket.User.Tenant)
+	title, description = redaction.Redact(title), redaction.Redact(description)
+	s.detectSimilar(ticket, title, description)
 
-	body, err := io.ReadAll(resp.Body)
+	tax := s.loadTaxonomy()
+	// Prior comments, attachment text and customer history, within the token
+	// budget. The suggestion can be posted to the customer as is, so internal
+	// notes are left out.
+	ticketContext := redaction.Redact(s.buildTicketContext(ticket, title, description, false))
+	logRedaction(redaction, "ticket "+ticketID.String())
+	// Knowledge base articles to ground the suggestion in
+	articles := s.retrieveArticles(title + "\n" + description)
+	tmpl := s.loadPrompt("classify", ticket.User.Tenant)
+	prompt, err := promptpkg.Render(tmpl.tmpl, map[string]string{
+		"Taxonomy": tax.describe(),
+		"Ticket":   ticketContext,
+		"Articles": describeArticles(articles, kbPromptTokens),
+		"Tenant":   ticket.User.Tenant,
+	})
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("render prompt %s: %w", tmpl.label, err)
 	}
-
-	var response map[string]interface{}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return fmt.Errorf("failed to parse Gemini response: %w", err)
+	// Whitespace edits and redelivered events produce the same prompt
+	hash := s.promptHash(ticket.User.Tenant, tmpl.label, prompt)
+	if !force && !isQueued(ticket.Status) && s.unchanged(ticketID, hash) {
+		log.Printf("Ticket %s unchanged since its last classification, skipping", ticketID)
+		return nil, nil
 	}
 
-	// Safe parsing
-	candidatesIface, ok := response["candidates"].(interface{})
-	if !ok || candidatesIface == nil {
-		log.Printf("No candidates in Gemini response: %s", string(body))
-		return s.updateTicketWithDefaults(ticketID)
+	run := &models.AIClassification{
+		TicketID:      ticketID,
+		Provider:      s.provider.Name(),
+		Model:         s.provider.Model(),
+		PromptVersion: tmpl.label,
+		Status:        models.ClassificationPending,
+		ContentHash:   hash,
 	}
-	candidates, ok := candidatesIface.([]interface{})
-	if !ok || len(candidates) == 0 {
-		log.Printf("Empty candidates in Gemini response: %s", string(body))
-		return s.updateTicketWithDefaults(ticketID)
+	started := time.Now()
+	var result *classification
+	var resp *llm.Response
+	cached := false
+	if !force {
+		result, resp, cached = s.cachedClassification(hash)
 	}
-	candidate := candidates[0].(map[string]interface{})
-	contentIface, ok := candidate["content"].(map[string]interface{})
-	if !ok {
-		log.Printf("No content in candidate: %s", string(body))
-		return s.updateTicketWithDefaults(ticketID)
+	if !cached {
+		result, resp, err = s.classify(ticketID, ticket.User.Tenant, prompt, tax)
 	}
-	partsIface, ok := contentIface["parts"].(interface{})
-	if !ok || partsIface == nil {
-		log.Printf("No parts in content: %s", string(body))
-		return s.updateTicketWithDefaults(ticketID)
+	run.Cached = cached
+	run.LatencyMS = time.Since(started).Milliseconds()
+	if resp != nil {
+		run.RawOutput = resp.Text
+		run.Model = resp.Model
+		run.InputTokens = resp.InputTokens
+		run.OutputTokens = resp.OutputTokens
 	}
-	parts, ok := partsIface.([]interface{})
-	if !ok || len(parts) == 0 {
-		log.Printf("Empty parts in content: %s", string(body))
-		return s.updateTicketWithDefaults(ticketID)
+	switch {
+	case errors.Is(err, llm.ErrUnavailable):
+		// Defaults would look like a real classification; wait for the provider instead
+		log.Printf("AI unavailable for ticket %s, leaving it queued: %v", ticketID, err)
+		ticket.Status = StatusQueued
+		applied, uerr := s.repo.ApplyClassification(ticket, oldStatus, nil)
+		if uerr != nil {
+			return nil, fmt.Errorf("update failed: %w", uerr)
+		}
+		if applied {
+			s.publishStatusChange(ticket, reportedStatus)
+		}
+		return nil, fmt.Errorf("ticket %s queued: %w", ticketID, err)
+	case errors.Is(err, llm.ErrEmptyResponse) || errors.Is(err, errInvalidClassification):
+		log.Printf("AI classification unusable for ticket %s: %v", ticketID, err)
+		result = fallbackClassification() // Confidence 0, so it always waits for review
+	case err != nil:
+		return nil, err
+	default:
+		run.Valid = true
+		if !cached {
+			s.cacheClassification(hash, result, resp)
+		}
 	}
-	part := parts[0].(map[string]interface{})
-	text, ok := part["text"].(string)
-	if !ok {
-		log.Printf("No text in part: %s", string(body))
-		return s.updateTicketWithDefaults(ticketID)
+	// Escalation is part of what the pipeline proposes, so reviews and
+	// accuracy reports see the escalated priority
+	priority, rule := result.Priority, ""
+	if run.Valid {
+		priority, rule = s.escalate(result, tax)
 	}
+	run.Category = result.Category
+	run.Priority = priority
+	run.Escalation = rule
+	run.Sentiment = result.Sentiment
+	run.Urgency = result.Urgency
+	run.Language = result.Language
+	// The cache and raw output keep the placeholders
+	suggestion := redaction.Restore(result.Suggestion)
+	run.Suggestion = suggestion
+	run.Confidence = result.Confidence
+	run.ArticleIDs = citedArticles(result.Articles, articles)
 
-	// Fixed: Strip Markdown backticks and "json" label
-	text = strings.TrimSpace(text)
-	if strings.HasPrefix(text, "```json") {
-		text = strings.TrimPrefix(text, "```json")
-		text = strings.TrimSuffix(text, "```")
+	// Only confident results are written straight to the ticket
+	if run.Valid && result.Confidence >= s.cfg.AutoApplyThreshold {
+		run.Status = models.ClassificationAutoApplied
+		ticket.Category = result.Category
+		ticket.Priority = priority
+		ticket.Suggestion = suggestion
+		ticket.Status = "classified"
+	} else {
+		ticket.Status = "pending_review"
+		// An upset customer shouldn't wait for the review to be escalated
+		if rule != "" && priorityRank(priority, tax) > priorityRank(ticket.Priority, tax) {
+			ticket.Priority = priority
+		}
 	}
-	text = strings.TrimSpace(text)
-	log.Printf("Cleaned Gemini text: %s", text)
-
-	// Parse JSON from cleaned text
-	var aiResponse struct {
-		Category   string `json:"category"`
-		Priority   string `json:"priority"`
-		Suggestion string `json:"suggestion"`
+	ticket.AIConfidence = result.Confidence
+	if run.Valid {
+		ticket.Sentiment = result.Sentiment
+		ticket.Urgency = result.Urgency
+		ticket.Language = result.Language
+		s.translateForAgents(ticket, title, description, redaction)
 	}
-	if err := json.Unmarshal([]byte(text), &aiResponse); err != nil {
-		log.Printf("Failed to parse AI JSON: %v (raw cleaned: %s)", err, text)
-		return s.updateTicketWithDefaults(ticketID)
+	escalated := rule != "" && priorityRank(ticket.Priority, tax) > priorityRank(oldPriority, tax)
+	if escalated {
+		now := time.Now()
+		ticket.EscalatedAt = &now
 	}
+	ticket.ArticleIDs = run.ArticleIDs
 
-	// Fetch and update ticket
-	ticket, err := s.repo.GetByID(ticketID)
+	if err := s.repo.CreateClassification(run); err != nil {
+		return nil, fmt.Errorf("failed to record classification: %w", err)
+	}
+	// Only the AI's own columns are written, and only if nobody changed the
+	// status while the LLM was working; the answer also needs the ticket to
+	// still be unassigned
+	var answer *models.Comment
+	classifiedStatus := ticket.Status
+	if s.shouldAutoResolve(ticket, run, tax) {
+		answer = s.autoResolve(ticket)
+	}
+	applied, err := s.repo.ApplyClassification(ticket, oldStatus, answer)
 	if err != nil {
-		return fmt.Errorf("ticket not found: %w", err)
+		return nil, fmt.Errorf("update failed: %w", err)
+	}
+	if !applied && answer != nil {
+		log.Printf("Ticket %s was picked up while being classified, not auto-resolving it", ticketID)
+		answer, ticket.Status, ticket.AutoResolvedAt, ticket.PendingCustomerSince = nil, classifiedStatus, nil, nil
+		applied, err = s.repo.ApplyClassification(ticket, oldStatus, nil)
+		if err != nil {
+			return nil, fmt.Errorf("update failed: %w", err)
+		}
+	}
+	if !applied {
+		log.Printf("Ticket %s changed while being classified, recorded run %s without applying it", ticketID, run.ID)
+		return run, nil
 	}
-	ticket.Category = aiResponse.Category
-	ticket.Priority = aiResponse.Priority
-	ticket.Suggestion = aiResponse.Suggestion
-	ticket.Status = "classified"
-	if err := s.repo.Update(ticket); err != nil {
-		return fmt.Errorf("update failed: %w", err)
+	s.publishStatusChange(ticket, reportedStatus)
+	if answer != nil {
+		log.Printf("Auto-resolved ticket %s with articles %v", ticketID, run.ArticleIDs)
+		s.publishComment(ticket, answer)
+	}
+	if escalated {
+		log.Printf("Escalated ticket %s from %s to %s (rule %q)", ticketID, oldPriority, ticket.Priority, rule)
+		s.publishEscalation(ticket, oldPriority, rule)
 	}
 
-	log.Printf("AI processed ticket %s: Category=%s, Priority=%s, Suggestion=%s", ticketID, aiResponse.Category, aiResponse.Priority, aiResponse.Suggestion)
-	return nil
+	log.Printf("AI processed ticket %s (%s): Category=%s, Priority=%s, Confidence=%.2f, Sentiment=%s, Urgency=%.2f, Language=%s, Suggestion=%s", ticketID, run.Status, run.Category, run.Priority, run.Confidence, run.Sentiment, run.Urgency, run.Language, result.Suggestion)
+	return run, nil
 }
 
-// Fallback update with defaults if Gemini fails
-func (s *aiService) updateTicketWithDefaults(ticketID uuid.UUID) error {
-	ticket, err := s.repo.GetByID(ticketID)
+// kbPromptTokens caps the article section, on top of the ticket context budget
+const kbPromptTokens = 1500
+
+var errInvalidClassification = errors.New("AI output failed validation")
+
+// classify asks the provider for a classification, using its structured
+// output mode when available. Output that fails validation gets exactly one
+// repair re-prompt that quotes the bad answer and what was wrong with it.
+func (s *aiService) classify(ticketID uuid.UUID, tenant, prompt string, tax taxonomy) (*classification, *llm.Response, error) {
+	req := llm.Request{Prompt: prompt, Temperature: 0.1, MaxOutputTokens: 1000}
+	if s.provider.SupportsStructuredOutput() {
+		req.Schema = tax.schema()
+	} else {
+		req.Prompt += "\n" + tax.instructions()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	log.Printf("Making %s API call for ticket ID: %s", s.provider.Name(), ticketID)
+	resp, err := s.generate(ctx, tenant, req)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	ticket.Category = "Unknown"
-	ticket.Priority = "low"
-	ticket.Suggestion = "Please provide more details for assistance."
-	ticket.Status = "classified"
-	if err := s.repo.Update(ticket); err != nil {
-		return err
+	result, verr := tax.parseClassification(resp.Text)
+	if verr == nil {
+		return result, resp, nil
+	}
+
+	log.Printf("AI output for ticket %s failed validation (%v), re-prompting once: %s", ticketID, verr, resp.Text)
+	req.Prompt = fmt.Sprintf(`%s
+
+Your previous answer was rejected:
+%s
+Problem: %s
+Answer again with corrected values only. %s`, req.Prompt, resp.Text, verr, tax.instructions())
+	retry, err := s.generate(ctx, tenant, req)
+	if err != nil {
+		return nil, resp, err
+	}
+	// Both calls are billed
+	retry.InputTokens += resp.InputTokens
+	retry.OutputTokens += resp.OutputTokens
+	result, verr = tax.parseClassification(retry.Text)
+	if verr != nil {
+		return nil, retry, fmt.Errorf("%w: %v", errInvalidClassification, verr)
+	}
+	return result, retry, nil
+}
+
+// fallbackClassification is proposed when the model gives nothing usable
+func fallbackClassification() *classification {
+	return &classification{
+		Category:   "Unknown",
+		Priority:   "low",
+		Suggestion: "Please provide more details for assistance.",
+		Confidence: 0,
+		Sentiment:  "neutral",
 	}
-	log.Printf("AI fallback updated ticket %s with defaults", ticketID)
-	return nil
 }
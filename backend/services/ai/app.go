@@ -1,48 +1,53 @@
 package ai
 
 import (
+	"ai-ticketing-backend/internal/pkg/config"
 	"ai-ticketing-backend/internal/pkg/db"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"ai-ticketing-backend/internal/pkg/storage"
+	"ai-ticketing-backend/services/ai/embedding"
+	"ai-ticketing-backend/services/ai/llm"
 	"ai-ticketing-backend/services/ai/repository"
-	"fmt"
-	"os"
+	"log"
 
-	"github.com/joho/godotenv"
+	"github.com/segmentio/kafka-go"
 )
 
-func Setup() AIService {
-	_ = godotenv.Load()
-
+func Setup(cfg *config.Config) AIService {
 	// DB setup (shared)
-	host := os.Getenv("DB_HOST")
-	if host == "" {
-		host = "postgres"
-	}
-	username := os.Getenv("DB_USERNAME")
-	if username == "" {
-		username = "ticket_user"
-	}
-	pass := os.Getenv("DB_PASSWORD")
-	if pass == "" {
-		pass = "ticket123"
-	}
-	dbname := os.Getenv("DB_DATABASE")
-	if dbname == "" {
-		dbname = "Ticket"
-	}
-	port := os.Getenv("DB_PORT")
-	if port == "" {
-		port = "5432"
+	dbConn, err := db.New(cfg.DB.DSN())
+	if err != nil {
+		panic(err)
 	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC", host, username, pass, dbname, port)
-
-	dbConn, err := db.New(dsn)
+	// Attachments are optional context; classification still works without them
+	store, err := storage.New(cfg.Storage)
 	if err != nil {
-		panic(err)
+		log.Printf("Attachment storage unavailable, classifying without attachments: %v", err)
+		store = nil
 	}
 
 	repo := repository.NewTicketRepository(dbConn)
-	svc := NewAIService(repo)
+	provider, err := llm.New(cfg.AI)
+	if err != nil {
+		panic(err)
+	}
+	provider = llm.NewGuardedProvider(provider, cfg.AI)
+	// Duplicate detection is optional too
+	embedder, err := embedding.New(cfg.AI)
+	if err != nil {
+		log.Printf("Embeddings unavailable, skipping duplicate detection: %v", err)
+		embedder = nil
+	}
+	// Status changes (auto-resolution) go to the same topic the ticket service uses
+	producer := &kafka.Writer{
+		Addr:     kafka.TCP(cfg.Kafka.Brokers...),
+		Topic:    cfg.Kafka.Topic,
+		Balancer: &kafka.Hash{}, // Keyed by ticket ID, so each ticket's events stay in order
+	}
+	// Cached results are an optimisation; a Redis outage only means more LLM calls
+	cache := redis.New(cfg.Redis.Addr)
+	svc := NewAIService(repo, store, provider, embedder, producer, cache, cfg.AI)
 
 	return svc
 }
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// autoResolveFooter follows the AI's answer so the customer knows how to
+// close or continue the ticket
+const autoResolveFooter = "\n\nDid this solve your problem? Confirm to close this ticket, or reply if you still need help and an agent will pick it up."
+
+// shouldAutoResolve reports whether the AI may answer the customer
+// directly: the category opted in, the classification was confident
+// enough, the answer is backed by a knowledge base article, and no agent
+// has touched the ticket. Each ticket is auto-resolved at most once; both
+// are checked again when the answer is written.
+func (s *aiService) shouldAutoResolve(ticket *models.Ticket, run *models.AIClassification, tax taxonomy) bool {
+	if run.Status != models.ClassificationAutoApplied || run.Confidence < s.cfg.AutoResolveThreshold || len(run.ArticleIDs) == 0 {
+		return false
+	}
+	if ticket.AgentID != nil || ticket.AutoResolvedAt != nil {
+		return false
+	}
+	for _, c := range tax.categories {
+		if c.Name == run.Category {
+			return c.AutoResolve
+		}
+	}
+	return false
+}
+
+// autoResolve moves the ticket to wait for the customer and returns the
+// suggestion as the public answer to post with it; the ticket service
+// closes or reopens it from there
+func (s *aiService) autoResolve(ticket *models.Ticket) *models.Comment {
+	// The suggestion is already in the customer's language; the footer isn't
+	footer := autoResolveFooter
+	if ticket.Language != "" && ticket.Language != "en" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		translated, err := s.translateText(ctx, ticket.User.Tenant, strings.TrimSpace(autoResolveFooter), ticket.Language)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to translate auto-resolve footer into %s: %v", ticket.Language, err)
+		} else {
+			footer = "\n\n" + translated
+		}
+	}
+	comment := &models.Comment{
+		TicketID:   ticket.ID,
+		AuthorID:   uuid.Nil,
+		AuthorRole: "ai",
+		Body:       ticket.Suggestion + footer,
+		Public:     true,
+	}
+	now := time.Now()
+	ticket.Status = "pending_customer"
+	ticket.AutoResolvedAt = &now
+	ticket.PendingCustomerSince = &now
+	return comment
+}
+
+// publishStatusChange tells the notification service and cache invalidator
+// about a status the AI service set
+func (s *aiService) publishStatusChange(ticket *models.Ticket, oldStatus string) {
+	if s.producer == nil || oldStatus == ticket.Status {
+		return
+	}
+	event := models.TicketUpdatedEvent{
+		Type:      models.EventTicketUpdated,
+		EventID:   uuid.New().String(),
+		TicketID:  ticket.ID,
+		UserID:    ticket.UserID,
+		OldStatus: oldStatus,
+		NewStatus: ticket.Status,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to marshal updated event: %v", err)
+		return
+	}
+	if err := s.producer.WriteMessages(context.Background(), kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes}); err != nil {
+		log.Printf("failed to produce updated event: %v", err)
+	} else {
+		log.Println("Published ticket_updated event for ID:", ticket.ID)
+	}
+}
+
+// publishComment announces the AI's answer like the ticket service announces
+// any other comment, so the customer is notified and streams show it
+func (s *aiService) publishComment(ticket *models.Ticket, comment *models.Comment) {
+	if s.producer == nil {
+		return
+	}
+	event := models.TicketContentUpdatedEvent{
+		Type:        models.EventTicketContentUpdated,
+		EventID:     uuid.New().String(),
+		TicketID:    ticket.ID,
+		UserID:      ticket.UserID,
+		Title:       ticket.Title,
+		Description: ticket.Description,
+		CommentID:   &comment.ID,
+		AuthorID:    &comment.AuthorID,
+		AuthorRole:  comment.AuthorRole,
+		Internal:    !comment.Public,
+		UpdatedAt:   time.Now().Format(time.RFC3339),
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to marshal content updated event: %v", err)
+		return
+	}
+	if err := s.producer.WriteMessages(context.Background(), kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes}); err != nil {
+		log.Printf("failed to produce content updated event: %v", err)
+	} else {
+		log.Println("Published ticket_content_updated event for comment on ID:", ticket.ID)
+	}
+}
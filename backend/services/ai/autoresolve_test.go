@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestShouldAutoResolve(t *testing.T) {
+	tax := newTaxonomy([]models.Category{
+		{Name: "Account", AutoResolve: true},
+		{Name: "Billing"},
+	}, models.DefaultPriorities)
+	agent, earlier := uuid.New(), time.Now().Add(-time.Hour)
+	run := func(change func(r *models.AIClassification)) *models.AIClassification {
+		r := &models.AIClassification{
+			Status:     models.ClassificationAutoApplied,
+			Category:   "Account",
+			Confidence: 0.95,
+			ArticleIDs: []uuid.UUID{uuid.New()},
+		}
+		if change != nil {
+			change(r)
+		}
+		return r
+	}
+	tests := []struct {
+		name   string
+		ticket models.Ticket
+		run    *models.AIClassification
+		want   bool
+	}{
+		{"eligible", models.Ticket{}, run(nil), true},
+		{"category not opted in", models.Ticket{}, run(func(r *models.AIClassification) { r.Category = "Billing" }), false},
+		{"category not in the taxonomy", models.Ticket{}, run(func(r *models.AIClassification) { r.Category = "Legacy" }), false},
+		{"below the threshold", models.Ticket{}, run(func(r *models.AIClassification) { r.Confidence = 0.89 }), false},
+		{"at the threshold", models.Ticket{}, run(func(r *models.AIClassification) { r.Confidence = 0.9 }), true},
+		{"no article to back it", models.Ticket{}, run(func(r *models.AIClassification) { r.ArticleIDs = nil }), false},
+		{"waiting for review", models.Ticket{}, run(func(r *models.AIClassification) { r.Status = models.ClassificationPending }), false},
+		{"agent assigned", models.Ticket{AgentID: &agent}, run(nil), false},
+		{"auto-resolved before", models.Ticket{AutoResolvedAt: &earlier}, run(nil), false},
+	}
+	s := &aiService{cfg: config.AIConfig{AutoResolveThreshold: 0.9}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.shouldAutoResolve(&tt.ticket, tt.run, tax); got != tt.want {
+				t.Errorf("shouldAutoResolve = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		language   string
+		translated string // Scripted translation of the footer; empty when none is asked for
+		wantFooter string
+	}{
+		{"english", "en", "", autoResolveFooter},
+		{"unknown language", "", "", autoResolveFooter},
+		{"translated footer", "de", "Hat das geholfen?", "\n\nHat das geholfen?"},
+		{"translation failed", "fr", "", autoResolveFooter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &scriptedProvider{}
+			if tt.translated != "" {
+				provider.texts = []string{tt.translated}
+			}
+			s := &aiService{provider: provider, repo: &usageRepo{}}
+			ticket := &models.Ticket{ID: uuid.New(), Status: "open", Language: tt.language, Suggestion: "Reset it from the login page."}
+
+			comment := s.autoResolve(ticket)
+			if want := ticket.Suggestion + tt.wantFooter; comment.Body != want {
+				t.Errorf("comment = %q, want %q", comment.Body, want)
+			}
+			if !comment.Public || comment.AuthorRole != "ai" || comment.TicketID != ticket.ID {
+				t.Errorf("comment = %+v, want a public AI answer on the ticket", comment)
+			}
+			if ticket.Status != "pending_customer" || ticket.AutoResolvedAt == nil || ticket.PendingCustomerSince == nil {
+				t.Errorf("ticket = %+v, want it waiting for the customer and marked auto-resolved", ticket)
+			}
+			if tt.language == "de" && !strings.Contains(provider.prompts[0], strings.TrimSpace(autoResolveFooter)) {
+				t.Errorf("translation prompt doesn't hold the footer:\n%s", provider.prompts[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/llm"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// errBudgetExceeded wraps llm.ErrUnavailable so tickets wait for the next day
+// the same way they wait out an outage
+var errBudgetExceeded = fmt.Errorf("%w: daily AI budget exhausted", llm.ErrUnavailable)
+
+// usageTracker keeps today's usage per tenant in memory, loaded from the DB
+// on first use so restarts don't reset the budget
+type usageTracker struct {
+	mu    sync.Mutex
+	day   string
+	usage map[string]*models.AIUsage
+}
+
+// generate makes an LLM call on behalf of tenant, enforcing and recording
+// the tenant's daily budget. Every AI feature goes through here.
+func (s *aiService) generate(ctx context.Context, tenant string, req llm.Request) (*llm.Response, error) {
+	if tenant == "" {
+		tenant = models.DefaultTenant
+	}
+	if err := s.checkBudget(tenant); err != nil {
+		return nil, err
+	}
+	resp, err := s.provider.Generate(ctx, req)
+	if resp != nil {
+		s.recordUsage(tenant, resp.InputTokens, resp.OutputTokens)
+	}
+	return resp, err
+}
+
+// stream is generate for answers sent to fn as they are written
+func (s *aiService) stream(ctx context.Context, tenant string, req llm.Request, fn func(chunk string) error) (*llm.Response, error) {
+	if tenant == "" {
+		tenant = models.DefaultTenant
+	}
+	if err := s.checkBudget(tenant); err != nil {
+		return nil, err
+	}
+	resp, err := llm.Stream(ctx, s.provider, req, fn)
+	if resp != nil {
+		s.recordUsage(tenant, resp.InputTokens, resp.OutputTokens)
+	}
+	return resp, err
+}
+
+func (s *aiService) checkBudget(tenant string) error {
+	maxTokens, maxCost := s.cfg.Budget(tenant)
+	if maxTokens <= 0 && maxCost <= 0 {
+		return nil
+	}
+	u := s.todaysUsage(tenant)
+	s.usage.mu.Lock()
+	defer s.usage.mu.Unlock()
+	if maxTokens > 0 && u.InputTokens+u.OutputTokens >= maxTokens {
+		return fmt.Errorf("%w: tenant %s used %d of %d tokens", errBudgetExceeded, tenant, u.InputTokens+u.OutputTokens, maxTokens)
+	}
+	if maxCost > 0 && u.Cost >= maxCost {
+		return fmt.Errorf("%w: tenant %s spent $%.2f of $%.2f", errBudgetExceeded, tenant, u.Cost, maxCost)
+	}
+	return nil
+}
+
+func (s *aiService) recordUsage(tenant string, inputTokens, outputTokens int) {
+	delta := &models.AIUsage{
+		Tenant:       tenant,
+		Day:          today(),
+		Calls:        1,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Cost:         s.cfg.Cost(inputTokens, outputTokens),
+		UpdatedAt:    time.Now(),
+	}
+	u := s.todaysUsage(tenant)
+	s.usage.mu.Lock()
+	u.Calls++
+	u.InputTokens += inputTokens
+	u.OutputTokens += outputTokens
+	u.Cost += delta.Cost
+	s.usage.mu.Unlock()
+	if err := s.repo.AddUsage(delta); err != nil {
+		log.Printf("Failed to record AI usage for tenant %s: %v", tenant, err)
+	}
+}
+
+// todaysUsage returns the in-memory counters for tenant, starting fresh each UTC day
+func (s *aiService) todaysUsage(tenant string) *models.AIUsage {
+	day := today()
+	s.usage.mu.Lock()
+	if s.usage.day != day || s.usage.usage == nil {
+		s.usage.day = day
+		s.usage.usage = map[string]*models.AIUsage{}
+	}
+	if u, ok := s.usage.usage[tenant]; ok {
+		s.usage.mu.Unlock()
+		return u
+	}
+	s.usage.mu.Unlock()
+
+	u, err := s.repo.GetUsage(tenant, day)
+	if err != nil {
+		log.Printf("Failed to load AI usage for tenant %s: %v", tenant, err)
+	}
+	if u == nil {
+		u = &models.AIUsage{Tenant: tenant, Day: day}
+	}
+	s.usage.mu.Lock()
+	defer s.usage.mu.Unlock()
+	if existing, ok := s.usage.usage[tenant]; ok {
+		return existing
+	}
+	s.usage.usage[tenant] = u
+	return u
+}
+
+func today() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
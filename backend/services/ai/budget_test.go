@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/services/ai/llm"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGenerateEnforcesBudget(t *testing.T) {
+	cfg := config.AIConfig{
+		DailyTokenBudget:  1000,
+		TenantBudgets:     []string{"acme:0:0.01", "free:0:0"},
+		InputCostPerMTok:  10,
+		OutputCostPerMTok: 100,
+	}
+	tests := []struct {
+		name    string
+		tenant  string
+		used    models.AIUsage // Already spent today
+		wantErr bool
+	}{
+		{"default budget left", "", models.AIUsage{InputTokens: 800, OutputTokens: 100}, false},
+		{"default budget used up", "", models.AIUsage{InputTokens: 900, OutputTokens: 100}, true},
+		{"tenant override has no token limit", "acme", models.AIUsage{InputTokens: 1_000_000}, false},
+		{"tenant cost budget used up", "acme", models.AIUsage{Cost: 0.01}, true},
+		{"unlimited tenant", "free", models.AIUsage{InputTokens: 1_000_000, Cost: 100}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &scriptedProvider{texts: []string{"answer"}}
+			repo := &usageRepo{}
+			s := &aiService{provider: provider, repo: repo, cfg: cfg}
+			tenant := tt.tenant
+			if tenant == "" {
+				tenant = models.DefaultTenant
+			}
+			used := tt.used
+			s.todaysUsage(tenant)
+			*s.usage.usage[tenant] = used
+
+			_, err := s.generate(context.Background(), tt.tenant, llm.Request{})
+			if tt.wantErr {
+				if !errors.Is(err, errBudgetExceeded) || !errors.Is(err, llm.ErrUnavailable) {
+					t.Fatalf("generate error = %v, want errBudgetExceeded", err)
+				}
+				if len(provider.prompts) != 0 || len(repo.added) != 0 {
+					t.Error("over-budget call reached the provider")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(repo.added) != 1 || repo.added[0].Tenant != tenant {
+				t.Fatalf("recorded usage %+v, want one call for %s", repo.added, tenant)
+			}
+			// 100 input and 10 output tokens per scripted answer
+			got := s.usage.usage[tenant]
+			if got.Calls != used.Calls+1 || got.InputTokens != used.InputTokens+100 || got.OutputTokens != used.OutputTokens+10 {
+				t.Errorf("today's usage = %+v, want the call added to %+v", got, used)
+			}
+			if want := (100*10 + 10*100) / 1e6; repo.added[0].Cost != want {
+				t.Errorf("recorded cost = %v, want %v", repo.added[0].Cost, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// taxonomy is the set of values the classifier may return, with the
+// admin-written descriptions and examples that go into the prompt
+type taxonomy struct {
+	Categories []string
+	Priorities []string
+	categories []models.Category
+	priorities []models.Priority
+}
+
+func newTaxonomy(categories []models.Category, priorities []models.Priority) taxonomy {
+	t := taxonomy{categories: categories, priorities: priorities}
+	for _, c := range categories {
+		t.Categories = append(t.Categories, c.Name)
+	}
+	for _, p := range priorities {
+		t.Priorities = append(t.Priorities, p.Name)
+	}
+	return t
+}
+
+// taxonomyCache avoids a DB round-trip per ticket; admin edits show up within taxonomyTTL
+type taxonomyCache struct {
+	mu       sync.Mutex
+	value    taxonomy
+	loadedAt time.Time
+}
+
+const taxonomyTTL = time.Minute
+
+// loadTaxonomy returns the active taxonomy from the DB, cached briefly
+func (s *aiService) loadTaxonomy() taxonomy {
+	s.taxonomy.mu.Lock()
+	defer s.taxonomy.mu.Unlock()
+	if !s.taxonomy.loadedAt.IsZero() && time.Since(s.taxonomy.loadedAt) < taxonomyTTL {
+		return s.taxonomy.value
+	}
+
+	categories, err := s.repo.ListCategories()
+	if err != nil || len(categories) == 0 {
+		log.Printf("Using default categories (load error: %v)", err)
+		categories = models.DefaultCategories
+	}
+	priorities, err := s.repo.ListPriorities()
+	if err != nil || len(priorities) == 0 {
+		log.Printf("Using default priorities (load error: %v)", err)
+		priorities = models.DefaultPriorities
+	}
+	s.taxonomy.value = newTaxonomy(categories, priorities)
+	s.taxonomy.loadedAt = time.Now()
+	return s.taxonomy.value
+}
+
+// describe renders the categories and priorities as a prompt section
+func (t taxonomy) describe() string {
+	var sb strings.Builder
+	sb.WriteString("Categories:\n")
+	for _, c := range t.categories {
+		fmt.Fprintf(&sb, "- %s: %s", c.Name, c.Description)
+		if len(c.Examples) > 0 {
+			fmt.Fprintf(&sb, " (e.g. %s)", strings.Join(quoteAll(c.Examples), ", "))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Priorities (lowest to highest):\n")
+	for _, p := range t.priorities {
+		fmt.Fprintf(&sb, "- %s: %s", p.Name, p.Description)
+		if len(p.Examples) > 0 {
+			fmt.Fprintf(&sb, " (e.g. %s)", strings.Join(quoteAll(p.Examples), ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func quoteAll(items []string) []string {
+	out := make([]string, len(items))
+	for i, s := range items {
+		out[i] = fmt.Sprintf("%q", s)
+	}
+	return out
+}
+
+// priorityAliases maps common model wording onto our priority levels
+var priorityAliases = map[string]string{
+	"urgent":   "high",
+	"critical": "high",
+	"p1":       "high",
+	"normal":   "medium",
+	"moderate": "medium",
+	"p2":       "medium",
+	"minor":    "low",
+	"trivial":  "low",
+	"p3":       "low",
+}
+
+// classification is a validated AI result
+type classification struct {
+	Category   string   `json:"category"`
+	Priority   string   `json:"priority"`
+	Suggestion string   `json:"suggestion"`
+	Confidence float64  `json:"confidence"`
+	Articles   []string `json:"articles"` // Knowledge base article ids the suggestion relies on
+	Sentiment  string   `json:"sentiment"`
+	Urgency    float64  `json:"urgency"`
+	Language   string   `json:"language"` // ISO 639-1
+}
+
+// Sentiments the classifier may report, calmest first
+var sentiments = []string{"positive", "neutral", "frustrated", "angry"}
+
+// sentimentAliases maps common model wording onto our sentiments
+var sentimentAliases = map[string]string{
+	"happy":     "positive",
+	"satisfied": "positive",
+	"calm":      "neutral",
+	"negative":  "frustrated",
+	"annoyed":   "frustrated",
+	"upset":     "frustrated",
+	"furious":   "angry",
+	"hostile":   "angry",
+}
+
+// schema is the JSON schema requested from providers with structured output
+func (t taxonomy) schema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "OBJECT",
+		"properties": map[string]interface{}{
+			"category":   map[string]interface{}{"type": "STRING", "enum": t.Categories},
+			"priority":   map[string]interface{}{"type": "STRING", "enum": t.Priorities},
+			"suggestion": map[string]interface{}{"type": "STRING", "description": "1-2 sentence reply to the customer, in their language"},
+			"confidence": map[string]interface{}{"type": "NUMBER", "description": "0 to 1, how sure you are of category and priority"},
+			"articles": map[string]interface{}{
+				"type":        "ARRAY",
+				"items":       map[string]interface{}{"type": "STRING"},
+				"description": "ids of the knowledge base articles the suggestion is based on, empty if none",
+			},
+			"sentiment": map[string]interface{}{"type": "STRING", "enum": sentiments, "description": "the customer's mood"},
+			"urgency":   map[string]interface{}{"type": "NUMBER", "description": "0 to 1, how time-critical the issue is for the customer"},
+			"language":  map[string]interface{}{"type": "STRING", "description": "ISO 639-1 code of the language the customer wrote in, e.g. en"},
+		},
+		"required":         []string{"category", "priority", "suggestion", "confidence", "sentiment", "urgency", "language"},
+		"propertyOrdering": []string{"category", "priority", "suggestion", "confidence", "articles", "sentiment", "urgency", "language"},
+	}
+}
+
+// instructions describes the expected output for providers without schema support
+func (t taxonomy) instructions() string {
+	return fmt.Sprintf(`JSON only: {"category": "%s", "priority": "%s", "suggestion": "1-2 sentence reply", "confidence": 0.0-1.0, "articles": ["cited article id", ...], "sentiment": "%s", "urgency": 0.0-1.0, "language": "ISO 639-1 code"}`,
+		strings.Join(t.Categories, "|"), strings.Join(t.Priorities, "|"), strings.Join(sentiments, "|"))
+}
+
+// parseClassification extracts the JSON object from raw model text and
+// validates it. Values that differ only in case or use a known alias are
+// normalized; anything else is rejected so it never reaches the DB.
+func (t taxonomy) parseClassification(raw string) (*classification, error) {
+	text := strings.TrimSpace(raw)
+	// Tolerate ```json fences and chatter around the object
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("response is not a JSON object")
+	}
+	text = text[start : end+1]
+
+	var c classification
+	if err := json.Unmarshal([]byte(text), &c); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var problems []string
+	if v, ok := matchValue(c.Category, t.Categories, nil); ok {
+		c.Category = v
+	} else {
+		problems = append(problems, fmt.Sprintf("category %q is not one of %s", c.Category, strings.Join(t.Categories, ", ")))
+	}
+	if v, ok := matchValue(c.Priority, t.Priorities, priorityAliases); ok {
+		c.Priority = v
+	} else {
+		problems = append(problems, fmt.Sprintf("priority %q is not one of %s", c.Priority, strings.Join(t.Priorities, ", ")))
+	}
+	c.Suggestion = strings.TrimSpace(c.Suggestion)
+	if c.Suggestion == "" {
+		problems = append(problems, "suggestion is empty")
+	}
+	// Some models answer in percent
+	if c.Confidence > 1 && c.Confidence <= 100 {
+		c.Confidence /= 100
+	}
+	if c.Confidence < 0 || c.Confidence > 1 {
+		problems = append(problems, fmt.Sprintf("confidence %v is outside 0..1", c.Confidence))
+	}
+	if v, ok := matchValue(c.Sentiment, sentiments, sentimentAliases); ok {
+		c.Sentiment = v
+	} else {
+		problems = append(problems, fmt.Sprintf("sentiment %q is not one of %s", c.Sentiment, strings.Join(sentiments, ", ")))
+	}
+	if c.Urgency > 1 && c.Urgency <= 100 {
+		c.Urgency /= 100
+	}
+	if c.Urgency < 0 || c.Urgency > 1 {
+		problems = append(problems, fmt.Sprintf("urgency %v is outside 0..1", c.Urgency))
+	}
+	// Language is informational; an odd code is dropped rather than failing the run
+	if c.Language = normalizeLanguage(c.Language); c.Language == "" {
+		log.Printf("AI output has no usable language code")
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return &c, nil
+}
+
+// matchValue finds v in allowed case-insensitively, then via aliases
+func matchValue(v string, allowed []string, aliases map[string]string) (string, bool) {
+	v = strings.TrimSpace(v)
+	for _, a := range allowed {
+		if strings.EqualFold(v, a) {
+			return a, true
+		}
+	}
+	if alias, ok := aliases[strings.ToLower(v)]; ok {
+		return matchValue(alias, allowed, nil)
+	}
+	return "", false
+}
+
+// normalizeLanguage reduces a model's language answer ("EN", "en-US",
+// "pt_BR") to a two-letter ISO 639-1 code, or "" when it isn't one
+func normalizeLanguage(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if i := strings.IndexAny(v, "-_"); i >= 0 {
+		v = v[:i]
+	}
+	if len(v) != 2 || v[0] < 'a' || v[0] > 'z' || v[1] < 'a' || v[1] > 'z' {
+		return ""
+	}
+	return v
+}
@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/llm"
+	"ai-ticketing-backend/services/ai/repository"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// scriptedProvider answers each call with the next of its texts
+type scriptedProvider struct {
+	texts   []string
+	prompts []string
+}
+
+func (p *scriptedProvider) Name() string                   { return "scripted" }
+func (p *scriptedProvider) Model() string                  { return "scripted-1" }
+func (p *scriptedProvider) SupportsStructuredOutput() bool { return false }
+
+func (p *scriptedProvider) Generate(ctx context.Context, req llm.Request) (*llm.Response, error) {
+	p.prompts = append(p.prompts, req.Prompt)
+	if len(p.prompts) > len(p.texts) {
+		return nil, errors.New("no more scripted answers")
+	}
+	return &llm.Response{Text: p.texts[len(p.prompts)-1], InputTokens: 100, OutputTokens: 10}, nil
+}
+
+// usageRepo keeps AI usage in memory; nothing else is needed to classify
+type usageRepo struct {
+	repository.TicketRepository
+	added []models.AIUsage
+}
+
+func (r *usageRepo) GetUsage(tenant, day string) (*models.AIUsage, error) { return nil, nil }
+
+func (r *usageRepo) AddUsage(usage *models.AIUsage) error {
+	r.added = append(r.added, *usage)
+	return nil
+}
+
+const validAnswer = `{"category": "Billing", "priority": "high", "suggestion": "We refunded the charge.", "confidence": 0.9, "sentiment": "angry", "urgency": 0.8, "language": "en"}`
+
+func TestParseClassification(t *testing.T) {
+	tax := newTaxonomy(models.DefaultCategories, models.DefaultPriorities)
+	tests := []struct {
+		name    string
+		raw     string
+		want    *classification // Nil when the answer is rejected
+		wantErr string
+	}{
+		{
+			name: "valid",
+			raw:  validAnswer,
+			want: &classification{Category: "Billing", Priority: "high", Suggestion: "We refunded the charge.", Confidence: 0.9, Sentiment: "angry", Urgency: 0.8, Language: "en"},
+		},
+		{
+			name: "fenced with chatter",
+			raw:  "Sure! Here it is:\n```json\n" + validAnswer + "\n```\nAnything else?",
+			want: &classification{Category: "Billing", Priority: "high", Suggestion: "We refunded the charge.", Confidence: 0.9, Sentiment: "angry", Urgency: 0.8, Language: "en"},
+		},
+		{
+			name: "case, aliases and percentages normalized",
+			raw:  `{"category": "billing", "priority": "Urgent", "suggestion": "  Refunded. ", "confidence": 85, "sentiment": "furious", "urgency": 40, "language": "EN-us"}`,
+			want: &classification{Category: "Billing", Priority: "high", Suggestion: "Refunded.", Confidence: 0.85, Sentiment: "angry", Urgency: 0.4, Language: "en"},
+		},
+		{
+			name: "odd language dropped",
+			raw:  `{"category": "Bug", "priority": "low", "suggestion": "Try again.", "confidence": 0.5, "sentiment": "neutral", "urgency": 0.1, "language": "english"}`,
+			want: &classification{Category: "Bug", Priority: "low", Suggestion: "Try again.", Confidence: 0.5, Sentiment: "neutral", Urgency: 0.1},
+		},
+		{name: "not JSON", raw: "I think this is a billing issue.", wantErr: "not a JSON object"},
+		{name: "broken JSON", raw: `{"category": "Billing",}`, wantErr: "invalid JSON"},
+		{name: "unknown category", raw: strings.Replace(validAnswer, `"Billing"`, `"Payments"`, 1), wantErr: `category "Payments" is not one of Billing, Bug, Feature, Support`},
+		{name: "unknown priority", raw: strings.Replace(validAnswer, `"high"`, `"blocker"`, 1), wantErr: `priority "blocker"`},
+		{name: "empty suggestion", raw: strings.Replace(validAnswer, "We refunded the charge.", " ", 1), wantErr: "suggestion is empty"},
+		{name: "confidence out of range", raw: strings.Replace(validAnswer, "0.9", "-1", 1), wantErr: "confidence -1 is outside 0..1"},
+		{name: "unknown sentiment", raw: strings.Replace(validAnswer, `"angry"`, `"sarcastic"`, 1), wantErr: `sentiment "sarcastic"`},
+		{name: "urgency out of range", raw: strings.Replace(validAnswer, "0.8", "250", 1), wantErr: "urgency 250 is outside 0..1"},
+		{
+			name:    "every problem reported",
+			raw:     `{"category": "Payments", "priority": "blocker", "suggestion": "", "confidence": 0.5, "sentiment": "neutral", "urgency": 0.1}`,
+			wantErr: `category "Payments" is not one of Billing, Bug, Feature, Support; priority "blocker" is not one of low, medium, high; suggestion is empty`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tax.parseClassification(tt.raw)
+			if tt.want == nil {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseClassification error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Category != tt.want.Category || got.Priority != tt.want.Priority || got.Suggestion != tt.want.Suggestion ||
+				got.Confidence != tt.want.Confidence || got.Sentiment != tt.want.Sentiment || got.Urgency != tt.want.Urgency || got.Language != tt.want.Language {
+				t.Errorf("parseClassification = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyRepairsOnce(t *testing.T) {
+	invalid := strings.Replace(validAnswer, `"Billing"`, `"Payments"`, 1)
+	tests := []struct {
+		name      string
+		answers   []string
+		wantCalls int
+		wantErr   error // Nil when a classification comes back
+	}{
+		{"valid first time", []string{validAnswer}, 1, nil},
+		{"repaired", []string{invalid, validAnswer}, 2, nil},
+		{"still invalid after the repair", []string{invalid, invalid, validAnswer}, 2, errInvalidClassification},
+	}
+	tax := newTaxonomy(models.DefaultCategories, models.DefaultPriorities)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &scriptedProvider{texts: tt.answers}
+			repo := &usageRepo{}
+			s := &aiService{provider: provider, repo: repo}
+			result, resp, err := s.classify(uuid.New(), "acme", "Classify this ticket.", tax)
+			if len(provider.prompts) != tt.wantCalls {
+				t.Fatalf("%d calls, want %d", len(provider.prompts), tt.wantCalls)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("classify error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || result.Category != "Billing" {
+				t.Fatalf("classify = %+v, %v, want the Billing classification", result, err)
+			}
+			if tt.wantCalls > 1 {
+				repair := provider.prompts[1]
+				if !strings.Contains(repair, invalid) || !strings.Contains(repair, `category "Payments" is not one of`) {
+					t.Errorf("repair prompt doesn't quote the rejected answer and its problem:\n%s", repair)
+				}
+			}
+			// Every call is billed to the tenant and counted in the response
+			if len(repo.added) != tt.wantCalls || repo.added[0].Tenant != "acme" {
+				t.Errorf("recorded usage %+v, want %d calls for acme", repo.added, tt.wantCalls)
+			}
+			if resp.InputTokens != 100*tt.wantCalls {
+				t.Errorf("response counts %d input tokens, want %d", resp.InputTokens, 100*tt.wantCalls)
+			}
+		})
+	}
+}
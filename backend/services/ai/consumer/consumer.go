@@ -2,69 +2,196 @@ package consumer
 
 import (
 	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/redis"
 	ai "ai-ticketing-backend/services/ai"
+	"ai-ticketing-backend/services/ai/llm"
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
 )
 
-func StartConsumer(aiSvc ai.AIService) {
-	kafkaBroker := os.Getenv("KAFKA_BROKER")
-	if kafkaBroker == "" {
-		kafkaBroker = "kafka:9092"
-	}
+// StartConsumer handles ticket events on a pool of workers. Offsets are
+// committed manually, only once every earlier message of the partition has
+// been handled, so a crash redelivers unfinished work instead of losing it;
+// events that were handled but not yet committed are skipped via Redis. An
+// event that fails is retried, and is never marked handled until it works.
+func StartConsumer(aiSvc ai.AIService, cache *redis.Client, kafkaCfg config.KafkaConfig, aiCfg config.AIConfig) {
 	r := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  strings.Split(kafkaBroker, ","),
-		Topic:    "ticket-events",
+		Brokers:  kafkaCfg.Brokers,
+		Topic:    kafkaCfg.Topic,
 		GroupID:  "ai-consumer-group",
 		MinBytes: 10e3, // 10KB
 		MaxBytes: 10e6, // 10MB
 	})
 	defer r.Close()
 
-	log.Println("AI Consumer listening on ticket-events...")
-	sigchan := make(chan os.Signal, 1)
-	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	for {
-		select {
-		case sig := <-sigchan:
-			log.Println("Caught signal", sig)
+	offsets := newOffsetTracker()
+	commits := make(chan kafka.Message, 64)
+	committed := make(chan struct{})
+	go func() {
+		commitLoop(r, commits)
+		close(committed)
+	}()
+
+	seen := &eventLog{cache: cache, ttl: aiCfg.EventDedupTTL}
+	p := newPool(aiCfg.Workers, aiCfg.WorkerQueue, func(msg kafka.Message) {
+		id := eventID(msg)
+		if seen.handled(id) {
+			log.Printf("Skipping redelivered event %s", id)
+		} else if err := handleWithRetry(ctx, aiSvc, msg); err != nil {
+			// Not done, so the offset stays uncommitted and the event is
+			// redelivered after a restart
+			log.Printf("Giving up on event %s at shutdown: %v", id, err)
 			return
-		default:
-			msg, err := r.ReadMessage(context.Background())
-			if err != nil {
-				log.Printf("Error reading message: %v", err)
-				continue
-			}
-			var createdEvent models.TicketCreatedEvent
-			if err := json.Unmarshal(msg.Value, &createdEvent); err == nil && createdEvent.TicketID != uuid.Nil {
-				if err := aiSvc.ProcessTicketEvent(&createdEvent); err != nil {
-					log.Printf("Failed to process created event %s: %v", createdEvent.TicketID, err)
-				} else {
-					log.Printf("AI processed created ticket %s successfully", createdEvent.TicketID)
-				}
-				continue
+		}
+		if next, ok := offsets.done(msg); ok {
+			commits <- next
+		}
+	})
+	log.Printf("AI Consumer listening on %s with %d workers", kafkaCfg.Topic, p.size())
+
+	for {
+		msg, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Println("Shutting down AI consumer")
+				break
 			}
+			log.Printf("Error reading message: %v", err)
+			continue
+		}
+		offsets.add(msg)
+		p.dispatch(msg) // Blocks while the worker's queue is full
+	}
+
+	// Let queued messages finish so their offsets are committed
+	p.close()
+	close(commits)
+	<-committed
+}
+
+// commitLoop commits offsets one at a time. Workers can hand over their
+// commits out of order, so anything at or below what was already committed
+// for the partition is skipped rather than moving the offset backwards.
+func commitLoop(r *kafka.Reader, commits <-chan kafka.Message) {
+	committed := map[int]int64{}
+	for msg := range commits {
+		if last, ok := committed[msg.Partition]; ok && msg.Offset <= last {
+			continue
+		}
+		committed[msg.Partition] = msg.Offset
+		if err := r.CommitMessages(context.Background(), msg); err != nil {
+			log.Printf("Failed to commit offset %d of partition %d: %v", msg.Offset, msg.Partition, err)
+		}
+	}
+}
 
-			var contentUpdatedEvent models.TicketContentUpdatedEvent
-			if err := json.Unmarshal(msg.Value, &contentUpdatedEvent); err == nil && contentUpdatedEvent.TicketID != uuid.Nil {
-				if err := aiSvc.ProcessTicketContent(contentUpdatedEvent.TicketID, contentUpdatedEvent.Title, contentUpdatedEvent.Description); err != nil {
-					log.Printf("Failed to process content updated event %s: %v", contentUpdatedEvent.TicketID, err)
-				} else {
-					log.Printf("AI processed content updated ticket %s successfully", contentUpdatedEvent.TicketID)
-				}
-				continue
+// handleAttempts is how often an event is handled before its ticket is left
+// to the queue retries instead
+const handleAttempts = 3
+
+// handleWithRetry handles msg until it succeeds, backing off between
+// attempts. After handleAttempts failures it also tries to queue the ticket,
+// which hands it to ProcessQueued. It only gives up when ctx is cancelled.
+func handleWithRetry(ctx context.Context, aiSvc ai.AIService, msg kafka.Message) error {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := handleMessage(aiSvc, msg)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Failed to handle event %s (attempt %d): %v", eventID(msg), attempt, err)
+		if attempt >= handleAttempts {
+			qerr := queueTicket(aiSvc, msg)
+			if qerr == nil {
+				log.Printf("Left ticket of event %s to the queue retries", eventID(msg))
+				return nil
 			}
+			log.Printf("Failed to queue ticket of event %s: %v", eventID(msg), qerr)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
 
-			log.Printf("Failed to unmarshal event: %s", string(msg.Value))
+// queueTicket moves the event's ticket to ai_queued
+func queueTicket(aiSvc ai.AIService, msg kafka.Message) error {
+	var event struct {
+		TicketID uuid.UUID `json:"ticket_id"`
+	}
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return err
+	}
+	return aiSvc.QueueTicket(event.TicketID)
+}
+
+// handleMessage routes one ticket event to the AI service. It returns an
+// error only when handling it again could succeed: malformed events,
+// deleted tickets and tickets queued for the LLM count as handled.
+func handleMessage(aiSvc ai.AIService, msg kafka.Message) error {
+	switch models.EventType(msg.Value) {
+	case models.EventTicketCreated:
+		var createdEvent models.TicketCreatedEvent
+		if err := json.Unmarshal(msg.Value, &createdEvent); err != nil || createdEvent.TicketID == uuid.Nil {
+			log.Printf("Failed to unmarshal created event: %s", string(msg.Value))
+			return nil
+		}
+		if err := classified(aiSvc.ProcessTicketEvent(&createdEvent)); err != nil {
+			return err
+		}
+		log.Printf("AI processed created ticket %s", createdEvent.TicketID)
+	case models.EventTicketContentUpdated:
+		var contentUpdatedEvent models.TicketContentUpdatedEvent
+		if err := json.Unmarshal(msg.Value, &contentUpdatedEvent); err != nil || contentUpdatedEvent.TicketID == uuid.Nil {
+			log.Printf("Failed to unmarshal content updated event: %s", string(msg.Value))
+			return nil
+		}
+		// A new comment only changes the thread, not what the ticket is about
+		if contentUpdatedEvent.CommentID == nil {
+			if err := classified(aiSvc.ProcessTicketContent(contentUpdatedEvent.TicketID, contentUpdatedEvent.Title, contentUpdatedEvent.Description)); err != nil {
+				return err
+			}
+			log.Printf("AI processed content updated ticket %s", contentUpdatedEvent.TicketID)
 		}
+		// The summary catches up with the next change, so this isn't retried
+		if err := aiSvc.RefreshSummary(contentUpdatedEvent.TicketID); err != nil {
+			log.Printf("Failed to refresh summary for ticket %s: %v", contentUpdatedEvent.TicketID, err)
+		}
+	case models.EventTicketUpdated, models.EventTicketEscalated, models.EventTicketAssigned:
+		// Status, priority and assignment changes need no classification
+	default:
+		log.Printf("Unknown event: %s", string(msg.Value))
+	}
+	return nil
+}
+
+// classified drops the errors a retry can't fix: the ticket is gone, or it
+// was queued for the LLM and ProcessQueued picks it up
+func classified(err error) error {
+	switch {
+	case errors.Is(err, llm.ErrUnavailable):
+		log.Printf("Leaving ticket to the queue retries: %v", err)
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		log.Printf("Skipping event: %v", err)
+		return nil
 	}
+	return err
 }
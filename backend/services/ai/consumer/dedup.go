@@ -0,0 +1,53 @@
+package consumer
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"context"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// eventLog remembers which events were handled so redeliveries (after a
+// rebalance or a crash before the commit) don't run the AI twice. Lookups
+// fail open: with Redis down, events are simply handled again.
+type eventLog struct {
+	cache *redis.Client
+	ttl   time.Duration
+}
+
+func eventKey(id string) string {
+	return "ai:event:" + id
+}
+
+func (l *eventLog) handled(id string) bool {
+	if l.cache == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	n, err := l.cache.Exists(ctx, eventKey(id)).Result()
+	if err != nil {
+		log.Printf("Failed to check event %s: %v", id, err)
+		return false
+	}
+	return n > 0
+}
+
+func (l *eventLog) remember(id string) {
+	if l.cache == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := l.cache.Set(ctx, eventKey(id), 1, l.ttl).Err(); err != nil {
+		log.Printf("Failed to record event %s: %v", id, err)
+	}
+}
+
+// eventID is the id the producer gave the event, or its position in the topic
+func eventID(msg kafka.Message) string {
+	return models.EventID(msg.Value, msg.Topic, msg.Partition, msg.Offset)
+}
@@ -0,0 +1,125 @@
+package consumer
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// pool runs a fixed set of workers, each with its own bounded queue.
+// Messages are routed by ticket ID, so one ticket's events are handled in
+// order while different tickets are handled in parallel.
+type pool struct {
+	queues []chan kafka.Message
+	wg     sync.WaitGroup
+}
+
+func newPool(workers, queueSize int, handle func(kafka.Message)) *pool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	p := &pool{queues: make([]chan kafka.Message, workers)}
+	for i := range p.queues {
+		q := make(chan kafka.Message, queueSize)
+		p.queues[i] = q
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for msg := range q {
+				handle(msg)
+			}
+		}()
+	}
+	return p
+}
+
+func (p *pool) size() int { return len(p.queues) }
+
+// dispatch queues msg on its ticket's worker, blocking while that queue is
+// full; this is what slows fetching down when the LLM falls behind
+func (p *pool) dispatch(msg kafka.Message) {
+	h := fnv.New32a()
+	h.Write(messageKey(msg))
+	p.queues[h.Sum32()%uint32(len(p.queues))] <- msg
+}
+
+// close stops accepting messages and waits for the queued ones to finish
+func (p *pool) close() {
+	for _, q := range p.queues {
+		close(q)
+	}
+	p.wg.Wait()
+}
+
+// messageKey is the ticket ID the message is about: the Kafka key, or for
+// messages published before events were keyed, the ticket_id field
+func messageKey(msg kafka.Message) []byte {
+	if len(msg.Key) > 0 {
+		return msg.Key
+	}
+	var event struct {
+		TicketID string `json:"ticket_id"`
+	}
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return nil
+	}
+	return []byte(event.TicketID)
+}
+
+// offsetTracker works out which offsets are safe to commit. Messages finish
+// out of order across workers, but a partition's offset may only advance
+// past a message once it and everything before it is done.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+// partitionOffsets holds a partition's fetched but uncommitted messages in
+// offset order
+type partitionOffsets struct {
+	pending []kafka.Message
+	done    map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: map[int]*partitionOffsets{}}
+}
+
+// add records a fetched message; fetches arrive in offset order per partition
+func (t *offsetTracker) add(msg kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.partitions[msg.Partition]
+	if !ok {
+		p = &partitionOffsets{done: map[int64]bool{}}
+		t.partitions[msg.Partition] = p
+	}
+	p.pending = append(p.pending, msg)
+}
+
+// done marks msg handled. It returns the last message of the now-finished
+// contiguous prefix when that prefix grew, i.e. the message to commit.
+func (t *offsetTracker) done(msg kafka.Message) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.partitions[msg.Partition]
+	if !ok {
+		return kafka.Message{}, false
+	}
+	p.done[msg.Offset] = true
+
+	var last kafka.Message
+	advanced := false
+	for len(p.pending) > 0 && p.done[p.pending[0].Offset] {
+		last = p.pending[0]
+		delete(p.done, last.Offset)
+		p.pending = p.pending[1:]
+		advanced = true
+	}
+	return last, advanced
+}
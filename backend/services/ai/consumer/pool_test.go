@@ -0,0 +1,86 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestOffsetTrackerCommitsContiguousPrefix(t *testing.T) {
+	type step struct {
+		partition int
+		offset    int64
+		commit    int64 // Offset expected to be committed, -1 for none
+	}
+	tests := []struct {
+		name    string
+		fetched map[int][]int64
+		steps   []step
+	}{
+		{
+			name:    "in order",
+			fetched: map[int][]int64{0: {10, 11, 12}},
+			steps:   []step{{0, 10, 10}, {0, 11, 11}, {0, 12, 12}},
+		},
+		{
+			name:    "later message first waits for the earlier one",
+			fetched: map[int][]int64{0: {10, 11, 12}},
+			steps:   []step{{0, 11, -1}, {0, 12, -1}, {0, 10, 12}},
+		},
+		{
+			name:    "gap in the middle",
+			fetched: map[int][]int64{0: {10, 11, 12, 13}},
+			steps:   []step{{0, 10, 10}, {0, 12, -1}, {0, 13, -1}, {0, 11, 13}},
+		},
+		{
+			name:    "offsets need not be consecutive",
+			fetched: map[int][]int64{0: {5, 9, 20}},
+			steps:   []step{{0, 9, -1}, {0, 5, 9}, {0, 20, 20}},
+		},
+		{
+			name:    "partitions are independent",
+			fetched: map[int][]int64{0: {1, 2}, 1: {1, 2}},
+			steps:   []step{{1, 2, -1}, {0, 1, 1}, {1, 1, 2}, {0, 2, 2}},
+		},
+		{
+			name:    "unknown partition",
+			fetched: map[int][]int64{0: {1}},
+			steps:   []step{{3, 1, -1}, {0, 1, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newOffsetTracker()
+			for partition, offsets := range tt.fetched {
+				for _, offset := range offsets {
+					tracker.add(kafka.Message{Partition: partition, Offset: offset})
+				}
+			}
+			for i, s := range tt.steps {
+				next, ok := tracker.done(kafka.Message{Partition: s.partition, Offset: s.offset})
+				switch {
+				case s.commit < 0 && ok:
+					t.Errorf("step %d (partition %d offset %d): committed %d, want nothing", i, s.partition, s.offset, next.Offset)
+				case s.commit >= 0 && !ok:
+					t.Errorf("step %d (partition %d offset %d): committed nothing, want %d", i, s.partition, s.offset, s.commit)
+				case ok && (next.Offset != s.commit || next.Partition != s.partition):
+					t.Errorf("step %d (partition %d offset %d): committed %d/%d, want %d/%d", i, s.partition, s.offset, next.Partition, next.Offset, s.partition, s.commit)
+				}
+			}
+		})
+	}
+}
+
+func TestOffsetTrackerForgetsCommittedMessages(t *testing.T) {
+	tracker := newOffsetTracker()
+	for _, offset := range []int64{1, 2, 3} {
+		tracker.add(kafka.Message{Offset: offset})
+	}
+	for _, offset := range []int64{3, 1, 2} {
+		tracker.done(kafka.Message{Offset: offset})
+	}
+	p := tracker.partitions[0]
+	if len(p.pending) != 0 || len(p.done) != 0 {
+		t.Errorf("pending %d, done %d after every message finished, want 0 and 0", len(p.pending), len(p.done))
+	}
+}
@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+// estimateTokens is a cheap approximation (~4 chars per token) that is
+// close enough for budgeting without pulling in a tokenizer.
+func estimateTokens(s string) int {
+	return (len([]rune(s)) + 3) / 4
+}
+
+// truncateTokens cuts s to roughly maxTokens, keeping the start
+func truncateTokens(s string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= maxTokens*4 {
+		return s
+	}
+	return string(r[:maxTokens*4]) + " …[truncated]"
+}
+
+// budget hands out tokens section by section; whatever a section doesn't
+// use rolls over to the next one.
+type budget struct {
+	remaining int
+}
+
+// take returns the text truncated to at most share of what is left (0 < share <= 1)
+func (b *budget) take(text string, share float64) string {
+	limit := int(float64(b.remaining) * share)
+	text = truncateTokens(text, limit)
+	b.remaining -= estimateTokens(text)
+	if b.remaining < 0 {
+		b.remaining = 0
+	}
+	return text
+}
+
+// buildTicketContext assembles the ticket, its conversation, attachment text
+// and the customer's recent tickets into a prompt section that fits within
+// the configured token budget. Sections are ordered by importance so the
+// most recent conversation survives truncation first. Internal notes are
+// only included for output that agents alone see.
+func (s *aiService) buildTicketContext(ticket *models.Ticket, title, description string, internalNotes bool) string {
+	b := &budget{remaining: s.cfg.MaxPromptTokens}
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Title: %s\n", b.take(title, 0.1))
+	fmt.Fprintf(&sb, "Description: %s\n", b.take(description, 0.4))
+
+	if comments, err := s.repo.ListComments(ticket.ID); err != nil {
+		log.Printf("Failed to load comments for ticket %s: %v", ticket.ID, err)
+	} else if len(comments) > 0 {
+		// Newest first so older messages are the ones dropped
+		var lines []string
+		for i := len(comments) - 1; i >= 0; i-- {
+			c := comments[i]
+			visibility := ""
+			if !c.Public {
+				if !internalNotes {
+					continue
+				}
+				visibility = " (internal note)"
+			}
+			lines = append(lines, fmt.Sprintf("- [%s] %s%s: %s", c.CreatedAt.Format(time.RFC3339), c.AuthorRole, visibility, c.Body))
+		}
+		section := b.take(strings.Join(lines, "\n"), 0.5)
+		if section != "" {
+			sb.WriteString("\nConversation (newest first):\n" + section + "\n")
+		}
+	}
+
+	if text := s.attachmentText(ticket); text != "" {
+		section := b.take(text, 0.6)
+		if section != "" {
+			sb.WriteString("\nAttachments:\n" + section + "\n")
+		}
+	}
+
+	if s.cfg.HistoryTickets > 0 {
+		history, err := s.repo.ListRecentByUser(ticket.UserID, ticket.ID, s.cfg.HistoryTickets)
+		if err != nil {
+			log.Printf("Failed to load history for user %s: %v", ticket.UserID, err)
+		} else if len(history) > 0 {
+			var lines []string
+			for _, t := range history {
+				lines = append(lines, fmt.Sprintf("- [%s] %s (category: %s, status: %s): %s",
+					t.CreatedAt.Format("2006-01-02"), t.Title, t.Category, t.Status, truncateTokens(t.Description, 60)))
+			}
+			section := b.take(strings.Join(lines, "\n"), 1)
+			if section != "" {
+				sb.WriteString("\nCustomer's recent tickets:\n" + section + "\n")
+			}
+		}
+	}
+
+	log.Printf("Built context for ticket %s: ~%d tokens (budget %d)", ticket.ID, s.cfg.MaxPromptTokens-b.remaining, s.cfg.MaxPromptTokens)
+	return sb.String()
+}
+
+// attachmentText reads each attachment (up to the size cap) and joins the extracted text
+func (s *aiService) attachmentText(ticket *models.Ticket) string {
+	if s.store == nil {
+		return ""
+	}
+	attachments, err := s.repo.ListAttachments(ticket.ID)
+	if err != nil {
+		log.Printf("Failed to load attachments for ticket %s: %v", ticket.ID, err)
+		return ""
+	}
+
+	var parts []string
+	for _, a := range attachments {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		body, err := s.store.Get(ctx, a.StorageKey)
+		if err != nil {
+			cancel()
+			log.Printf("Failed to read attachment %s: %v", a.ID, err)
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(body, int64(s.cfg.MaxAttachmentBytes)))
+		body.Close()
+		cancel()
+		if err != nil {
+			log.Printf("Failed to read attachment %s: %v", a.ID, err)
+			continue
+		}
+
+		text := strings.TrimSpace(extractText(a.ContentType, data))
+		if text == "" {
+			parts = append(parts, fmt.Sprintf("[%s: %s, no extractable text]", a.Filename, a.ContentType))
+			continue
+		}
+		// Logs are most useful at the end (the error), so keep the tail
+		if a.ContentType == "text/plain" && estimateTokens(text) > 1500 {
+			r := []rune(text)
+			text = "[…] " + string(r[len(r)-1500*4:])
+		}
+		parts = append(parts, fmt.Sprintf("[%s]\n%s", a.Filename, text))
+	}
+	return strings.Join(parts, "\n\n")
+}
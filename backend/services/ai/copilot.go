@@ -0,0 +1,267 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	promptpkg "ai-ticketing-backend/internal/pkg/prompt"
+	"ai-ticketing-backend/services/ai/llm"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	copilotToolRounds    = 3    // Tool calls per question before answering with what was found
+	copilotHistoryTokens = 3000 // Earlier messages of the conversation, newest kept
+)
+
+// Caller is the user an AI request is made for, from their JWT
+type Caller struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// canView applies the ticket service's rules: customers see their own
+// tickets, agents every ticket
+func (c Caller) canView(t *models.Ticket) bool {
+	return c.Role == "agent" || t.UserID == c.UserID
+}
+
+func tenantOf(t *models.Ticket) string {
+	if t.User.Tenant == "" {
+		return models.DefaultTenant
+	}
+	return t.User.Tenant
+}
+
+// authorizeTicket loads a ticket the caller may see
+func (s *aiService) authorizeTicket(caller Caller, ticketID uuid.UUID) (*models.Ticket, error) {
+	ticket, err := s.repo.GetByID(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+	if !caller.canView(ticket) {
+		return nil, fmt.Errorf("unauthorized: not allowed to view ticket %s", ticketID)
+	}
+	return ticket, nil
+}
+
+// CopilotConversation returns one of the caller's conversations about the ticket
+func (s *aiService) CopilotConversation(caller Caller, ticketID, conversationID uuid.UUID) (*models.CopilotConversation, error) {
+	if _, err := s.authorizeTicket(caller, ticketID); err != nil {
+		return nil, err
+	}
+	conversation, err := s.repo.GetConversation(conversationID)
+	if err != nil || conversation.TicketID != ticketID || conversation.AgentID != caller.UserID {
+		return nil, fmt.Errorf("conversation not found")
+	}
+	return conversation, nil
+}
+
+// Copilot answers an agent's question about a ticket. The model may first
+// call tools, each reported to emit as a "tool" event; the answer is then
+// streamed as "chunk" events. The conversation, tool results included, is
+// stored so follow-up questions build on it.
+func (s *aiService) Copilot(caller Caller, ticketID uuid.UUID, req *models.CopilotRequest, emit func(event string, data interface{}) error) (*models.CopilotMessage, error) {
+	ticket, err := s.authorizeTicket(caller, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	conversation := &models.CopilotConversation{TicketID: ticketID, AgentID: caller.UserID}
+	if req.ConversationID != nil {
+		if conversation, err = s.CopilotConversation(caller, ticketID, *req.ConversationID); err != nil {
+			return nil, err
+		}
+	} else if err := s.repo.CreateConversation(conversation); err != nil {
+		return nil, fmt.Errorf("failed to start conversation: %w", err)
+	}
+	messages := conversation.Messages
+	conversation.Messages = nil
+	if err := emit("conversation", conversation); err != nil {
+		return nil, err
+	}
+
+	question := &models.CopilotMessage{ConversationID: conversation.ID, Role: models.CopilotAgent, Content: strings.TrimSpace(req.Message)}
+	if err := s.repo.AddCopilotMessage(question); err != nil {
+		return nil, fmt.Errorf("failed to save message: %w", err)
+	}
+	messages = append(messages, *question)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	tenant := tenantOf(ticket)
+	// Tool results are stored as-is and masked each time they are sent
+	redaction := s.redaction(tenant)
+	ticketContext := redaction.Redact(s.buildTicketContext(ticket, ticket.Title, ticket.Description, true))
+	var inputTokens, outputTokens int
+
+	for round := 0; round < copilotToolRounds; round++ {
+		call, resp, err := s.planCopilotStep(ctx, tenant, ticketContext, redaction.Redact(describeCopilot(messages, copilotHistoryTokens)))
+		if resp != nil {
+			inputTokens += resp.InputTokens
+			outputTokens += resp.OutputTokens
+		}
+		if errors.Is(err, llm.ErrUnavailable) {
+			return nil, err
+		}
+		if err != nil {
+			log.Printf("Copilot planning failed for ticket %s, answering without tools: %v", ticketID, err)
+			break
+		}
+		if call == nil {
+			break
+		}
+		call.Query = redaction.RestoreAll(call.Query)
+		if calledBefore(messages, call) {
+			break
+		}
+		if err := emit("tool", call); err != nil {
+			return nil, err
+		}
+		result, err := s.runCopilotTool(caller, ticketID, call)
+		if err != nil {
+			return nil, err
+		}
+		toolMessage := &models.CopilotMessage{ConversationID: conversation.ID, Role: models.CopilotTool, Tool: call.Tool, Query: call.Query, Content: result}
+		if err := s.repo.AddCopilotMessage(toolMessage); err != nil {
+			return nil, fmt.Errorf("failed to save tool result: %w", err)
+		}
+		messages = append(messages, *toolMessage)
+	}
+
+	tmpl := s.loadPrompt("copilot", tenant)
+	prompt, err := promptpkg.Render(tmpl.tmpl, map[string]string{
+		"Ticket":       ticketContext,
+		"Conversation": redaction.Redact(describeCopilot(messages, copilotHistoryTokens)),
+		"Language":     languageName(s.cfg.AgentLanguage),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("render prompt %s: %w", tmpl.label, err)
+	}
+	logRedaction(redaction, "copilot conversation "+conversation.ID.String())
+	restorer := redaction.Restorer(func(chunk string) error {
+		return emit("chunk", map[string]string{"text": chunk})
+	})
+	resp, err := s.stream(ctx, tenant, llm.Request{Prompt: prompt, Temperature: 0.3, MaxOutputTokens: 1500}, restorer.Write)
+	if err == nil {
+		err = restorer.Flush()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("copilot failed: %w", err)
+	}
+
+	answer := &models.CopilotMessage{
+		ConversationID: conversation.ID,
+		Role:           models.CopilotAssistant,
+		Content:        strings.TrimSpace(redaction.Restore(resp.Text)),
+		InputTokens:    inputTokens + resp.InputTokens,
+		OutputTokens:   outputTokens + resp.OutputTokens,
+	}
+	if err := s.repo.AddCopilotMessage(answer); err != nil {
+		return nil, fmt.Errorf("failed to save answer: %w", err)
+	}
+	log.Printf("Copilot answered on ticket %s (%d/%d tokens)", ticketID, answer.InputTokens, answer.OutputTokens)
+	return answer, nil
+}
+
+// planCopilotStep asks the model for the next tool call, or nil when it
+// can answer already
+func (s *aiService) planCopilotStep(ctx context.Context, tenant, ticketContext, conversation string) (*copilotCall, *llm.Response, error) {
+	var tools strings.Builder
+	names := []string{"answer"}
+	for _, t := range copilotTools {
+		fmt.Fprintf(&tools, "- %s: %s\n", t.name, t.description)
+		names = append(names, t.name)
+	}
+	req := llm.Request{
+		Prompt: fmt.Sprintf(`You help a support agent with the ticket below. Decide whether a tool would help answer the agent's latest question.
+Tools:
+%s
+Use "answer" when what you have is enough. Don't repeat a tool call that is already in the conversation.
+
+%s
+%s`, tools.String(), ticketContext, conversation),
+		Temperature:     0,
+		MaxOutputTokens: 200,
+	}
+	if s.provider.SupportsStructuredOutput() {
+		req.Schema = map[string]interface{}{
+			"type": "OBJECT",
+			"properties": map[string]interface{}{
+				"tool":  map[string]interface{}{"type": "STRING", "enum": names},
+				"query": map[string]interface{}{"type": "STRING"},
+				"days":  map[string]interface{}{"type": "INTEGER"},
+			},
+			"required": []string{"tool"},
+		}
+	} else {
+		req.Prompt += "\n\n" + `JSON only: {"tool": "tool name or answer", "query": "search text", "days": 0}`
+	}
+
+	resp, err := s.generate(ctx, tenant, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := resp.Text
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, resp, errors.New("tool choice is not a JSON object")
+	}
+	var call copilotCall
+	if err := json.Unmarshal([]byte(text[start:end+1]), &call); err != nil {
+		return nil, resp, fmt.Errorf("invalid tool choice JSON: %w", err)
+	}
+	call.Tool = strings.TrimSpace(call.Tool)
+	call.Query = strings.TrimSpace(call.Query)
+	if call.Tool == "answer" {
+		return nil, resp, nil
+	}
+	if findCopilotTool(call.Tool) == nil {
+		return nil, resp, fmt.Errorf("unknown tool %q", call.Tool)
+	}
+	return &call, resp, nil
+}
+
+// calledBefore reports whether the same tool call was already made in the conversation
+func calledBefore(messages []models.CopilotMessage, call *copilotCall) bool {
+	for _, m := range messages {
+		if m.Role == models.CopilotTool && m.Tool == call.Tool && strings.EqualFold(m.Query, call.Query) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeCopilot renders the conversation oldest first, dropping the
+// oldest messages when it exceeds maxTokens
+func describeCopilot(messages []models.CopilotMessage, maxTokens int) string {
+	remaining := maxTokens
+	var lines []string
+	for i := len(messages) - 1; i >= 0; i-- {
+		m := messages[i]
+		var line string
+		switch m.Role {
+		case models.CopilotTool:
+			line = fmt.Sprintf("Tool %s(%q) returned:\n%s", m.Tool, m.Query, m.Content)
+		case models.CopilotAssistant:
+			line = "You: " + m.Content
+		default:
+			line = "Agent: " + m.Content
+		}
+		if estimateTokens(line) > remaining && i < len(messages)-1 {
+			lines = append(lines, fmt.Sprintf("(%d earlier messages omitted)", i+1))
+			break
+		}
+		remaining -= estimateTokens(line)
+		lines = append(lines, line)
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return "Conversation with the agent:\n" + strings.Join(lines, "\n\n") + "\n"
+}
@@ -0,0 +1,30 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCallerCanView(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	ticket := &models.Ticket{UserID: owner, User: models.User{Tenant: "acme"}}
+	tests := []struct {
+		name   string
+		caller Caller
+		want   bool
+	}{
+		{"owner", Caller{UserID: owner, Role: "customer"}, true},
+		{"other customer", Caller{UserID: other, Role: "customer"}, false},
+		{"agent", Caller{UserID: other, Role: "agent"}, true},
+		{"no role", Caller{UserID: other}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.caller.canView(ticket); got != tt.want {
+				t.Errorf("canView = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/repository"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	copilotToolTokens = 1500 // Per tool result
+	copilotMaxTickets = 20   // Tickets listed per tool result
+)
+
+// copilotCall is the model's choice of tool
+type copilotCall struct {
+	Tool  string `json:"tool"`
+	Query string `json:"query,omitempty"`
+	Days  int    `json:"days,omitempty"` // customer_history: how far back; 0 for all
+}
+
+type copilotTool struct {
+	name        string
+	description string
+	run         func(s *aiService, caller Caller, ticket *models.Ticket, call *copilotCall) (string, error)
+}
+
+var copilotTools = []copilotTool{
+	{
+		name:        "customer_history",
+		description: `this customer's other tickets, newest first. "query" keeps only tickets mentioning any of its words; "days" limits how far back to look.`,
+		run:         (*aiService).customerHistory,
+	},
+	{
+		name:        "kb_search",
+		description: `search the published knowledge base articles for "query".`,
+		run:         (*aiService).searchKnowledgeBase,
+	},
+	{
+		name:        "similar_tickets",
+		description: `other customers' tickets similar to "query", or to this ticket when "query" is empty.`,
+		run:         (*aiService).similarTickets,
+	},
+}
+
+func findCopilotTool(name string) *copilotTool {
+	for i := range copilotTools {
+		if copilotTools[i].name == name {
+			return &copilotTools[i]
+		}
+	}
+	return nil
+}
+
+// runCopilotTool runs a tool call on behalf of caller. The ticket is
+// authorized again on every call, and tickets in the result are filtered
+// by the same rules. Only authorization errors are returned; other
+// failures are reported to the model as the tool's result.
+func (s *aiService) runCopilotTool(caller Caller, ticketID uuid.UUID, call *copilotCall) (string, error) {
+	ticket, err := s.authorizeTicket(caller, ticketID)
+	if err != nil {
+		return "", err
+	}
+	tool := findCopilotTool(call.Tool)
+	if tool == nil {
+		return "", fmt.Errorf("invalid tool %q", call.Tool)
+	}
+	result, err := tool.run(s, caller, ticket, call)
+	if err != nil {
+		log.Printf("Copilot tool %s failed on ticket %s: %v", call.Tool, ticketID, err)
+		return "The tool failed: " + err.Error(), nil
+	}
+	return truncateTokens(result, copilotToolTokens), nil
+}
+
+func (s *aiService) customerHistory(caller Caller, ticket *models.Ticket, call *copilotCall) (string, error) {
+	filter := repository.TicketFilter{UserID: ticket.UserID, Newest: true}
+	if call.Days > 0 {
+		filter.From = time.Now().AddDate(0, 0, -call.Days)
+	}
+	tickets, err := s.repo.FindTickets(filter)
+	if err != nil {
+		return "", err
+	}
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(call.Query)) {
+		if len(w) >= 3 {
+			words = append(words, w)
+		}
+	}
+
+	var lines []string
+	for i := range tickets {
+		t := &tickets[i]
+		if t.ID == ticket.ID || !caller.canView(t) || !mentionsAny(t, words) {
+			continue
+		}
+		lines = append(lines, describeTicketLine(t, ""))
+		if len(lines) == copilotMaxTickets {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return "No other tickets from this customer match.", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func mentionsAny(t *models.Ticket, words []string) bool {
+	if len(words) == 0 {
+		return true
+	}
+	text := strings.ToLower(t.Title + " " + t.Description)
+	for _, w := range words {
+		if strings.Contains(text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// embeddingQuery is what the search tools embed: the model's query or the
+// ticket itself, masked like everything else sent to the embedder
+func (s *aiService) embeddingQuery(ticket *models.Ticket, call *copilotCall) string {
+	query := call.Query
+	if query == "" {
+		query = ticket.Title + "\n" + ticket.Description
+	}
+	return s.redaction(tenantOf(ticket)).Redact(query)
+}
+
+func (s *aiService) searchKnowledgeBase(caller Caller, ticket *models.Ticket, call *copilotCall) (string, error) {
+	articles := s.retrieveArticles(s.embeddingQuery(ticket, call))
+	if len(articles) == 0 {
+		return "No knowledge base articles match.", nil
+	}
+	var sb strings.Builder
+	for _, a := range articles {
+		fmt.Fprintf(&sb, "- %s: %s\n", a.Title, truncateTokens(strings.ReplaceAll(a.Body, "\n", " "), copilotToolTokens/len(articles)))
+	}
+	return sb.String(), nil
+}
+
+func (s *aiService) similarTickets(caller Caller, ticket *models.Ticket, call *copilotCall) (string, error) {
+	similar, err := s.searchSimilar(tenantOf(ticket), s.embeddingQuery(ticket, call), ticket.ID, s.cfg.SimilarLimit)
+	if err != nil {
+		return "", err
+	}
+	ids := make([]uuid.UUID, len(similar))
+	scores := map[uuid.UUID]float64{}
+	for i, sim := range similar {
+		ids[i] = sim.SimilarTicketID
+		scores[sim.SimilarTicketID] = sim.Score
+	}
+	tickets, err := s.repo.ListTicketsByID(ids)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(tickets, func(i, j int) bool { return scores[tickets[i].ID] > scores[tickets[j].ID] })
+
+	var lines []string
+	for i := range tickets {
+		t := &tickets[i]
+		if !caller.canView(t) {
+			continue
+		}
+		lines = append(lines, describeTicketLine(t, fmt.Sprintf(", similarity %.2f", scores[t.ID])))
+	}
+	if len(lines) == 0 {
+		return "No similar tickets found.", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// describeTicketLine is one ticket in a tool result
+func describeTicketLine(t *models.Ticket, extra string) string {
+	return fmt.Sprintf("- [%s] %s (status %s, category %s, priority %s%s): %s",
+		t.CreatedAt.Format("2006-01-02"), t.Title, t.Status, t.Category, t.Priority, extra, truncateTokens(t.Description, 60))
+}
@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"ai-ticketing-backend/services/ai/llm"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// promptHash identifies a classification request. Whitespace is collapsed
+// so edits that only reflow the text hash the same.
+func (s *aiService) promptHash(tenant, promptVersion, prompt string) string {
+	h := sha256.New()
+	for _, part := range []string{s.provider.Name(), s.provider.Model(), promptVersion, tenant, strings.Join(strings.Fields(prompt), " ")} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// unchanged reports whether the ticket's latest classification was made
+// from the same prompt, in which case running it again would change nothing
+func (s *aiService) unchanged(ticketID uuid.UUID, hash string) bool {
+	last, err := s.repo.LatestClassification(ticketID)
+	if err != nil {
+		log.Printf("Failed to load last classification for ticket %s: %v", ticketID, err)
+		return false
+	}
+	return last != nil && last.Valid && last.ContentHash == hash
+}
+
+// cachedResult is a valid classification kept in Redis by prompt hash
+type cachedResult struct {
+	Result    *classification `json:"result"`
+	Model     string          `json:"model"`
+	RawOutput string          `json:"raw_output"`
+}
+
+func resultKey(hash string) string {
+	return "ai:result:" + hash
+}
+
+// cachedClassification returns the result of an earlier identical prompt.
+// The response carries the original output but no tokens, since none were spent.
+func (s *aiService) cachedClassification(hash string) (*classification, *llm.Response, bool) {
+	if s.cache == nil {
+		return nil, nil, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var cached cachedResult
+	if err := s.cache.CacheGet(ctx, resultKey(hash), &cached); err != nil || cached.Result == nil {
+		return nil, nil, false
+	}
+	return cached.Result, &llm.Response{Text: cached.RawOutput, Model: cached.Model}, true
+}
+
+func (s *aiService) cacheClassification(hash string, result *classification, resp *llm.Response) {
+	if s.cache == nil || resp == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	cached := cachedResult{Result: result, Model: resp.Model, RawOutput: resp.Text}
+	if err := s.cache.CacheSet(ctx, resultKey(hash), cached, s.cfg.ResultCacheTTL); err != nil {
+		log.Printf("Failed to cache classification: %v", err)
+	}
+}
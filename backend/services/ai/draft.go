@@ -0,0 +1,110 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	promptpkg "ai-ticketing-backend/internal/pkg/prompt"
+	"ai-ticketing-backend/services/ai/llm"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// toneInstructions tells the model what each draft tone means
+var toneInstructions = map[string]string{
+	models.ToneFormal:   "formal and polite, addressing the customer respectfully",
+	models.ToneFriendly: "warm and friendly, but professional",
+	models.ToneConcise:  "brief and to the point, a few sentences at most",
+}
+
+// DraftReply writes a reply to the customer, sending it to fn as it is
+// generated, and records the draft so sending it can be tracked
+func (s *aiService) DraftReply(caller Caller, ticketID uuid.UUID, req *models.DraftReplyRequest, fn func(chunk string) error) (*models.ReplyDraft, error) {
+	ticket, err := s.authorizeTicket(caller, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	tone := req.Tone
+	if tone == "" {
+		tone = models.ToneFriendly
+	}
+	toneText, ok := toneInstructions[tone]
+	if !ok {
+		return nil, fmt.Errorf("invalid tone %q", tone)
+	}
+	// The draft is sent to the customer, so it is written in their language
+	language := ticket.Language
+	if language == "" {
+		language = s.cfg.AgentLanguage
+	}
+
+	// Retrieve articles for the latest customer message too, which is
+	// often a different question from the original one
+	query := ticket.Title + "\n" + ticket.Description
+	if comments, err := s.repo.ListComments(ticketID); err != nil {
+		log.Printf("Failed to load comments for ticket %s: %v", ticketID, err)
+	} else {
+		for i := len(comments) - 1; i >= 0; i-- {
+			if comments[i].AuthorRole == "customer" {
+				query += "\n" + comments[i].Body
+				break
+			}
+		}
+	}
+	redaction := s.redaction(ticket.User.Tenant)
+	articles := s.retrieveArticles(redaction.Redact(query))
+
+	instructions := ""
+	if text := strings.TrimSpace(req.Instructions); text != "" {
+		instructions = "The agent asks you to: " + redaction.Redact(text) + "\n"
+	}
+	ticketContext := redaction.Redact(s.buildTicketContext(ticket, ticket.Title, ticket.Description, true))
+	logRedaction(redaction, "ticket "+ticketID.String())
+	tmpl := s.loadPrompt("reply", ticket.User.Tenant)
+	prompt, err := promptpkg.Render(tmpl.tmpl, map[string]string{
+		"Ticket":       ticketContext,
+		"Articles":     describeArticles(articles, kbPromptTokens),
+		"Tone":         toneText,
+		"Language":     languageName(language),
+		"Instructions": instructions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("render prompt %s: %w", tmpl.label, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	// The agent sees the customer's details again, not the placeholders
+	restorer := redaction.Restorer(fn)
+	resp, err := s.stream(ctx, ticket.User.Tenant, llm.Request{Prompt: prompt, Temperature: 0.4, MaxOutputTokens: 1500}, restorer.Write)
+	if err == nil {
+		err = restorer.Flush()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("draft failed: %w", err)
+	}
+
+	draft := &models.ReplyDraft{
+		TicketID:      ticketID,
+		AgentID:       caller.UserID,
+		Tone:          tone,
+		Language:      language,
+		Body:          strings.TrimSpace(redaction.Restore(resp.Text)),
+		Model:         resp.Model,
+		PromptVersion: tmpl.label,
+		InputTokens:   resp.InputTokens,
+		OutputTokens:  resp.OutputTokens,
+		Outcome:       models.DraftPending,
+	}
+	for _, a := range articles {
+		draft.ArticleIDs = append(draft.ArticleIDs, a.ID)
+	}
+	if err := s.repo.CreateDraft(draft); err != nil {
+		return nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+	log.Printf("Drafted %s reply for ticket %s (%d/%d tokens)", tone, ticketID, resp.InputTokens, resp.OutputTokens)
+	return draft, nil
+}
@@ -0,0 +1,64 @@
+// Package embedding turns ticket text into vectors for similarity search.
+package embedding
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"context"
+	"fmt"
+	"math"
+)
+
+// Embedder maps texts to vectors. Vectors from different models are not
+// comparable, so callers key stored vectors by Model().
+type Embedder interface {
+	Model() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// New builds the embedder named in cfg.EmbeddingProvider
+func New(cfg config.AIConfig) (Embedder, error) {
+	switch cfg.EmbeddingProvider {
+	case "hashing":
+		return NewHashingEmbedder(hashingDimensions), nil
+	case "gemini":
+		if cfg.GeminiAPIKey == "" {
+			return nil, fmt.Errorf("gemini embeddings require GEMINI_API_KEY")
+		}
+		return NewGeminiEmbedder(cfg.GeminiAPIKey, cfg.EmbeddingModel), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", cfg.EmbeddingProvider)
+	}
+}
+
+// Cosine returns the cosine similarity of a and b (0 if either is empty or
+// the lengths differ)
+func Cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func normalize(v []float32) []float32 {
+	var n float64
+	for _, x := range v {
+		n += float64(x) * float64(x)
+	}
+	if n == 0 {
+		return v
+	}
+	n = math.Sqrt(n)
+	for i := range v {
+		v[i] = float32(float64(v[i]) / n)
+	}
+	return v
+}
@@ -0,0 +1,85 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type geminiEmbedder struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func NewGeminiEmbedder(apiKey, model string) Embedder {
+	return &geminiEmbedder{apiKey: apiKey, model: model, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (e *geminiEmbedder) Model() string { return e.model }
+
+func (e *geminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type request struct {
+		Model   string `json:"model"`
+		Content struct {
+			Parts []part `json:"parts"`
+		} `json:"content"`
+		TaskType string `json:"taskType"`
+	}
+	var payload struct {
+		Requests []request `json:"requests"`
+	}
+	for _, t := range texts {
+		r := request{Model: "models/" + e.model, TaskType: "SEMANTIC_SIMILARITY"}
+		r.Content.Parts = []part{{Text: t}}
+		payload.Requests = append(payload.Requests, r)
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	url := "https://generativelanguage.googleapis.com/v1beta/models/" + e.model + ":batchEmbedContents"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", e.apiKey) // Not in the URL, which errors quote
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Gemini embedding call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Gemini embedding error %d: %s", resp.StatusCode, string(body))
+	}
+	var response struct {
+		Embeddings []struct {
+			Values []float32 `json:"values"`
+		} `json:"embeddings"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Gemini embedding response: %w", err)
+	}
+	if len(response.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("Gemini returned %d embeddings for %d texts", len(response.Embeddings), len(texts))
+	}
+	out := make([][]float32, len(texts))
+	for i, emb := range response.Embeddings {
+		out[i] = emb.Values
+	}
+	return out, nil
+}
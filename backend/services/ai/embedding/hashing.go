@@ -0,0 +1,78 @@
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const hashingDimensions = 512
+
+// stopWords carry no signal about what a ticket is about
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true, "is": true, "are": true,
+	"was": true, "were": true, "be": true, "to": true, "of": true, "in": true, "on": true, "for": true,
+	"with": true, "it": true, "this": true, "that": true, "i": true, "my": true, "me": true, "we": true,
+	"you": true, "your": true, "our": true, "at": true, "as": true, "by": true, "from": true, "have": true,
+	"has": true, "had": true, "do": true, "does": true, "did": true, "not": true, "can": true, "please": true,
+}
+
+// hashingEmbedder is the local fallback: word unigrams and bigrams are
+// hashed into a fixed number of buckets (the "hashing trick"). It needs no
+// network or training and catches reworded duplicates that share vocabulary.
+type hashingEmbedder struct {
+	dims int
+}
+
+func NewHashingEmbedder(dims int) Embedder {
+	return &hashingEmbedder{dims: dims}
+}
+
+func (e *hashingEmbedder) Model() string { return fmt.Sprintf("hashing-%d", e.dims) }
+
+func (e *hashingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = e.embed(text)
+	}
+	return out, nil
+}
+
+func (e *hashingEmbedder) embed(text string) []float32 {
+	v := make([]float32, e.dims)
+	words := tokenize(text)
+	for i, w := range words {
+		e.add(v, w, 1)
+		if i > 0 {
+			e.add(v, words[i-1]+" "+w, 0.5) // Bigrams keep some word order
+		}
+	}
+	return normalize(v)
+}
+
+// add hashes feature into a bucket; a second hash bit picks the sign so
+// collisions cancel out on average instead of piling up
+func (e *hashingEmbedder) add(v []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	v[sum%uint64(e.dims)] += weight
+}
+
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if len(f) > 1 && !stopWords[f] {
+			words = append(words, f)
+		}
+	}
+	return words
+}
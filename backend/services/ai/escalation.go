@@ -0,0 +1,172 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// escalationRule raises the priority when all of its conditions match.
+// The rule syntax is documented on config.AIConfig.EscalationRules.
+type escalationRule struct {
+	spec       string
+	sentiments []string // Any of these; empty matches any sentiment
+	minUrgency float64  // 0 matches any urgency
+	bump       int      // Levels to raise by, or
+	raiseTo    string   // the priority to raise to
+}
+
+// parseEscalationRules parses the configured rules, skipping invalid ones
+// so a typo doesn't stop classification
+func parseEscalationRules(specs []string) []escalationRule {
+	var rules []escalationRule
+	for _, spec := range specs {
+		rule, err := parseEscalationRule(spec)
+		if err != nil {
+			log.Printf("Ignoring escalation rule %q: %v", spec, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func parseEscalationRule(spec string) (escalationRule, error) {
+	rule := escalationRule{spec: strings.TrimSpace(spec)}
+	i := strings.LastIndex(rule.spec, ":")
+	if i < 0 {
+		return rule, fmt.Errorf("missing \":action\"")
+	}
+	conditions, action := strings.TrimSpace(rule.spec[:i]), strings.TrimSpace(rule.spec[i+1:])
+	if conditions == "" {
+		return rule, fmt.Errorf("no conditions")
+	}
+
+	for _, cond := range strings.Split(conditions, "&") {
+		cond = strings.TrimSpace(cond)
+		switch {
+		case strings.HasPrefix(cond, "sentiment="):
+			for _, v := range strings.Split(strings.TrimPrefix(cond, "sentiment="), "|") {
+				sentiment, ok := matchValue(v, sentiments, sentimentAliases)
+				if !ok {
+					return rule, fmt.Errorf("unknown sentiment %q", v)
+				}
+				rule.sentiments = append(rule.sentiments, sentiment)
+			}
+		case strings.HasPrefix(cond, "urgency>="):
+			min, err := strconv.ParseFloat(strings.TrimPrefix(cond, "urgency>="), 64)
+			if err != nil || min < 0 || min > 1 {
+				return rule, fmt.Errorf("urgency threshold must be between 0 and 1")
+			}
+			rule.minUrgency = min
+		default:
+			return rule, fmt.Errorf("unknown condition %q", cond)
+		}
+	}
+
+	if strings.HasPrefix(action, "+") {
+		n, err := strconv.Atoi(action[1:])
+		if err != nil || n < 1 {
+			return rule, fmt.Errorf("invalid action %q", action)
+		}
+		rule.bump = n
+	} else if action != "" {
+		// Checked against the taxonomy when applied, since admins can change it
+		rule.raiseTo = action
+	} else {
+		return rule, fmt.Errorf("empty action")
+	}
+	return rule, nil
+}
+
+func (r escalationRule) matches(c *classification) bool {
+	if c.Urgency < r.minUrgency {
+		return false
+	}
+	if len(r.sentiments) == 0 {
+		return true
+	}
+	for _, s := range r.sentiments {
+		if s == c.Sentiment {
+			return true
+		}
+	}
+	return false
+}
+
+// escalate applies the rules to the model's priority. When several match,
+// the highest resulting priority wins; priorities never go down.
+func (s *aiService) escalate(c *classification, tax taxonomy) (priority, rule string) {
+	base := priorityRank(c.Priority, tax)
+	if base < 0 {
+		return c.Priority, ""
+	}
+	best := base
+	for _, r := range s.rules {
+		if !r.matches(c) {
+			continue
+		}
+		rank := base + r.bump
+		if r.raiseTo != "" {
+			if rank = priorityRank(r.raiseTo, tax); rank < 0 {
+				log.Printf("Escalation rule %q names unknown priority %q", r.spec, r.raiseTo)
+				continue
+			}
+		}
+		if rank >= len(tax.Priorities) {
+			rank = len(tax.Priorities) - 1
+		}
+		if rank > best {
+			best, rule = rank, r.spec
+		}
+	}
+	return tax.Priorities[best], rule
+}
+
+// priorityRank is the position of a priority, lowest first, or -1
+func priorityRank(priority string, tax taxonomy) int {
+	for i, p := range tax.Priorities {
+		if strings.EqualFold(p, priority) {
+			return i
+		}
+	}
+	return -1
+}
+
+// publishEscalation tells the notification service and cache invalidator
+// that a rule raised the ticket's priority
+func (s *aiService) publishEscalation(ticket *models.Ticket, oldPriority, rule string) {
+	if s.producer == nil {
+		return
+	}
+	event := models.TicketEscalatedEvent{
+		Type:        models.EventTicketEscalated,
+		EventID:     uuid.New().String(),
+		TicketID:    ticket.ID,
+		UserID:      ticket.UserID,
+		OldPriority: oldPriority,
+		NewPriority: ticket.Priority,
+		Sentiment:   ticket.Sentiment,
+		Urgency:     ticket.Urgency,
+		Rule:        rule,
+		EscalatedAt: time.Now().Format(time.RFC3339),
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to marshal escalated event: %v", err)
+		return
+	}
+	if err := s.producer.WriteMessages(context.Background(), kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes}); err != nil {
+		log.Printf("failed to produce escalated event: %v", err)
+	} else {
+		log.Println("Published ticket_escalated event for ID:", ticket.ID)
+	}
+}
@@ -0,0 +1,93 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseEscalationRule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    escalationRule
+		wantErr string
+	}{
+		{spec: "sentiment=angry:+1", want: escalationRule{sentiments: []string{"angry"}, bump: 1}},
+		{spec: " sentiment=Angry|upset & urgency>=0.7 : high ", want: escalationRule{sentiments: []string{"angry", "frustrated"}, minUrgency: 0.7, raiseTo: "high"}},
+		{spec: "urgency>=0.9:+2", want: escalationRule{minUrgency: 0.9, bump: 2}},
+		{spec: "sentiment=angry", wantErr: `missing ":action"`},
+		{spec: ":+1", wantErr: "no conditions"},
+		{spec: "sentiment=angry:", wantErr: "empty action"},
+		{spec: "sentiment=sarcastic:+1", wantErr: `unknown sentiment "sarcastic"`},
+		{spec: "urgency>=2:+1", wantErr: "urgency threshold must be between 0 and 1"},
+		{spec: "urgency>=high:+1", wantErr: "urgency threshold must be between 0 and 1"},
+		{spec: "category=Billing:+1", wantErr: `unknown condition "category=Billing"`},
+		{spec: "sentiment=angry:+0", wantErr: `invalid action "+0"`},
+		{spec: "sentiment=angry:+x", wantErr: `invalid action "+x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseEscalationRule(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseEscalationRule error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got.sentiments, tt.want.sentiments) || got.minUrgency != tt.want.minUrgency ||
+				got.bump != tt.want.bump || got.raiseTo != tt.want.raiseTo {
+				t.Errorf("parseEscalationRule = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEscalationRulesSkipsInvalid(t *testing.T) {
+	rules := parseEscalationRules([]string{"sentiment=angry:+1", "nonsense", "urgency>=0.9:high"})
+	if len(rules) != 2 || rules[0].spec != "sentiment=angry:+1" || rules[1].spec != "urgency>=0.9:high" {
+		t.Errorf("parseEscalationRules = %+v, want the two valid rules", rules)
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	tax := newTaxonomy(models.DefaultCategories, models.DefaultPriorities)
+	tests := []struct {
+		name     string
+		rules    []string
+		c        classification
+		wantPrio string
+		wantRule string // Empty when no rule raised the priority
+	}{
+		{"no rules", nil, classification{Priority: "low", Sentiment: "angry", Urgency: 1}, "low", ""},
+		{"bumped", []string{"sentiment=angry:+1"}, classification{Priority: "low", Sentiment: "angry"}, "medium", "sentiment=angry:+1"},
+		{"sentiment doesn't match", []string{"sentiment=angry:+1"}, classification{Priority: "low", Sentiment: "neutral"}, "low", ""},
+		{"urgency below threshold", []string{"urgency>=0.8:+1"}, classification{Priority: "low", Urgency: 0.79}, "low", ""},
+		{"urgency at threshold", []string{"urgency>=0.8:+1"}, classification{Priority: "low", Urgency: 0.8}, "medium", "urgency>=0.8:+1"},
+		{"all conditions needed", []string{"sentiment=angry&urgency>=0.8:+1"}, classification{Priority: "low", Sentiment: "angry", Urgency: 0.5}, "low", ""},
+		{"bump capped at the top", []string{"sentiment=angry:+5"}, classification{Priority: "medium", Sentiment: "angry"}, "high", "sentiment=angry:+5"},
+		{"raised to a named priority", []string{"urgency>=0.9:high"}, classification{Priority: "low", Urgency: 0.95}, "high", "urgency>=0.9:high"},
+		{"never lowered", []string{"sentiment=angry:low"}, classification{Priority: "high", Sentiment: "angry"}, "high", ""},
+		{"unknown priority ignored", []string{"sentiment=angry:critical"}, classification{Priority: "low", Sentiment: "angry"}, "low", ""},
+		{
+			name:     "highest match wins",
+			rules:    []string{"sentiment=angry:+1", "urgency>=0.9:high", "sentiment=frustrated:+2"},
+			c:        classification{Priority: "low", Sentiment: "angry", Urgency: 0.95},
+			wantPrio: "high",
+			wantRule: "urgency>=0.9:high",
+		},
+		{"model priority outside the taxonomy kept", []string{"sentiment=angry:+1"}, classification{Priority: "blocker", Sentiment: "angry"}, "blocker", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &aiService{rules: parseEscalationRules(tt.rules)}
+			priority, rule := s.escalate(&tt.c, tax)
+			if priority != tt.wantPrio || rule != tt.wantRule {
+				t.Errorf("escalate = %q, %q, want %q, %q", priority, rule, tt.wantPrio, tt.wantRule)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// extractText returns the readable text of an attachment, or "" for
+// formats we can't read (images, archives).
+func extractText(contentType string, data []byte) string {
+	switch contentType {
+	case "text/plain", "application/json":
+		return strings.ToValidUTF8(string(data), "")
+	case "application/pdf":
+		return extractPDFText(data)
+	default:
+		return ""
+	}
+}
+
+var pdfStream = regexp.MustCompile(`(?s)<<(.*?)>>\s*stream\r?\n`)
+
+// extractPDFText pulls string operands of the text operators (Tj, TJ, ', ")
+// out of every content stream. It is not a full PDF parser but is enough for
+// the text-based PDFs customers export from other tools.
+func extractPDFText(data []byte) string {
+	var out strings.Builder
+	for _, loc := range pdfStream.FindAllSubmatchIndex(data, -1) {
+		dict := data[loc[2]:loc[3]]
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := data[start : start+end]
+
+		content := raw
+		if bytes.Contains(dict, []byte("/FlateDecode")) {
+			zr, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			content, err = io.ReadAll(io.LimitReader(zr, 8<<20))
+			zr.Close()
+			if err != nil && len(content) == 0 {
+				continue
+			}
+		} else if bytes.Contains(dict, []byte("/Filter")) {
+			continue // Other encodings (images, DCT) carry no text
+		}
+		pdfContentText(content, &out)
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// pdfContentText walks a content stream, collecting literal strings and
+// breaking lines at text-object and line-move operators.
+func pdfContentText(content []byte, out *strings.Builder) {
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '(':
+			s, n := pdfLiteral(content[i:])
+			out.WriteString(s)
+			i += n - 1
+		case 'E':
+			if i+1 < len(content) && content[i+1] == 'T' {
+				out.WriteByte('\n')
+			}
+		case 'T':
+			if i+1 < len(content) && (content[i+1] == '*' || content[i+1] == 'd' || content[i+1] == 'D') {
+				out.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// pdfLiteral decodes a (...) string with nesting and escapes, returning
+// the text and the number of bytes consumed.
+func pdfLiteral(b []byte) (string, int) {
+	var sb strings.Builder
+	depth := 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == '\\' && i+1 < len(b):
+			i++
+			switch b[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r', 't':
+				sb.WriteByte(' ')
+			case '(', ')', '\\':
+				sb.WriteByte(b[i])
+			default:
+				// Octal escape \ddd
+				j := i
+				v := 0
+				for j < len(b) && j < i+3 && b[j] >= '0' && b[j] <= '7' {
+					v = v*8 + int(b[j]-'0')
+					j++
+				}
+				if j > i {
+					sb.WriteRune(rune(v))
+					i = j - 1
+				}
+			}
+		case c == '(':
+			if depth > 0 {
+				sb.WriteByte(c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return validText(sb.String()), i + 1
+			}
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return validText(sb.String()), len(b)
+}
+
+func validText(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	return strings.ToValidUTF8(s, "")
+}
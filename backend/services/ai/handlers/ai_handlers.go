@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai"
+	"ai-ticketing-backend/services/ai/llm"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AIHandlers struct {
+	svc ai.AIService
+}
+
+func NewAIHandlers(svc ai.AIService) *AIHandlers {
+	return &AIHandlers{svc: svc}
+}
+
+// Translate for POST /api/v1/agent/tickets/:id/translate
+func (h *AIHandlers) Translate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	var req models.TranslateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	translation, err := h.svc.Translate(caller(c), id, req.Text, req.Language)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+// Summarize for POST /api/v1/agent/tickets/:id/summarize
+func (h *AIHandlers) Summarize(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	summary, err := h.svc.Summarize(caller(c), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// Reclassify for POST /api/v1/agent/tickets/:id/reclassify
+func (h *AIHandlers) Reclassify(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	run, err := h.svc.Reclassify(caller(c), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// DraftReply for POST /api/v1/agent/tickets/:id/draft-reply. The draft is
+// streamed as server-sent events: "chunk" events with {"text"} as it is
+// written, then "done" with the saved draft, whose id goes with the comment
+// when the agent sends it. Errors after streaming started come as "error".
+func (h *AIHandlers) DraftReply(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	var req models.DraftReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	streaming := false
+	draft, err := h.svc.DraftReply(caller(c), id, &req, func(chunk string) error {
+		if !streaming {
+			streaming = true
+			startStream(c)
+		}
+		c.SSEvent("chunk", gin.H{"text": chunk})
+		c.Writer.Flush()
+		return c.Request.Context().Err() // Stop generating once the agent is gone
+	})
+	if err != nil && !streaming {
+		writeError(c, err)
+		return
+	}
+	if err != nil {
+		c.SSEvent("error", gin.H{"error": err.Error()})
+	} else {
+		c.SSEvent("done", draft)
+	}
+	c.Writer.Flush()
+}
+
+// Copilot for POST /api/v1/agent/tickets/:id/copilot. Streams server-sent
+// events: "conversation" (pass its id to continue), "tool" for each tool
+// the AI calls, "chunk" events with {"text"} of the answer, then "done"
+// with the saved answer, or "error".
+func (h *AIHandlers) Copilot(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	var req models.CopilotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	streaming := false
+	answer, err := h.svc.Copilot(caller(c), id, &req, func(event string, data interface{}) error {
+		if !streaming {
+			streaming = true
+			startStream(c)
+		}
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+		return c.Request.Context().Err()
+	})
+	if err != nil && !streaming {
+		writeError(c, err)
+		return
+	}
+	if err != nil {
+		c.SSEvent("error", gin.H{"error": err.Error()})
+	} else {
+		c.SSEvent("done", answer)
+	}
+	c.Writer.Flush()
+}
+
+// Conversation for GET /api/v1/agent/tickets/:id/copilot/:conversationId
+func (h *AIHandlers) Conversation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	conversationID, err := uuid.Parse(c.Param("conversationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid conversation ID"})
+		return
+	}
+	conversation, err := h.svc.CopilotConversation(caller(c), id, conversationID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, conversation)
+}
+
+// caller is the authenticated user, as set by the auth middleware
+func caller(c *gin.Context) ai.Caller {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+	return ai.Caller{UserID: userID, Role: c.GetString("role")}
+}
+
+// startStream switches the response to server-sent events
+func startStream(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Don't let a proxy hold the stream back
+	c.Status(http.StatusOK)
+}
+
+// writeError maps service errors onto HTTP status codes
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, llm.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, ai.ErrTicketBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "unauthorized"):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "conversation not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "conversation not found"})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "ticket not found"})
+	case strings.Contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/embedding"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// articleIndex caches published article vectors; an article is only
+// re-embedded when its UpdatedAt changes. A refresh replaces entries with a
+// new map, so a map once returned is never written to.
+type articleIndex struct {
+	mu         sync.Mutex
+	entries    map[uuid.UUID]articleVector
+	loadedAt   time.Time
+	refreshing bool
+}
+
+type articleVector struct {
+	article models.Article
+	vector  []float32
+}
+
+const articleIndexTTL = 5 * time.Minute
+
+// articleEntries returns the article vectors, refreshing them once they are
+// older than articleIndexTTL. The refresh embeds changed articles without
+// holding the lock; other callers use the previous vectors meanwhile.
+func (s *aiService) articleEntries() map[uuid.UUID]articleVector {
+	s.articles.mu.Lock()
+	prev := s.articles.entries
+	if (prev != nil && time.Since(s.articles.loadedAt) < articleIndexTTL) || s.articles.refreshing {
+		s.articles.mu.Unlock()
+		return prev
+	}
+	s.articles.refreshing = true
+	s.articles.mu.Unlock()
+
+	entries := s.loadArticleVectors(prev)
+
+	s.articles.mu.Lock()
+	defer s.articles.mu.Unlock()
+	s.articles.refreshing = false
+	if entries != nil {
+		s.articles.entries = entries
+		s.articles.loadedAt = time.Now()
+	}
+	return s.articles.entries
+}
+
+// loadArticleVectors reads the published articles and embeds the ones that
+// changed since prev; it returns nil if the articles can't be read
+func (s *aiService) loadArticleVectors(prev map[uuid.UUID]articleVector) map[uuid.UUID]articleVector {
+	articles, err := s.repo.ListPublishedArticles()
+	if err != nil {
+		log.Printf("Failed to load articles, using cached index: %v", err)
+		return nil
+	}
+
+	entries := make(map[uuid.UUID]articleVector, len(articles))
+	var stale []models.Article
+	for _, a := range articles {
+		if prev, ok := prev[a.ID]; ok && prev.article.UpdatedAt.Equal(a.UpdatedAt) {
+			entries[a.ID] = prev
+			continue
+		}
+		stale = append(stale, a)
+	}
+	if len(stale) > 0 {
+		texts := make([]string, len(stale))
+		for i, a := range stale {
+			texts[i] = a.Title + "\n" + a.Body
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		vectors, err := s.embedder.Embed(ctx, texts)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to embed %d articles: %v", len(stale), err)
+		} else {
+			for i, a := range stale {
+				entries[a.ID] = articleVector{article: a, vector: vectors[i]}
+			}
+		}
+	}
+	return entries
+}
+
+// retrieveArticles returns the published articles closest to the ticket
+// text, best first, for grounding the suggestion. The text goes to the
+// embedder and must already be redacted.
+func (s *aiService) retrieveArticles(text string) []models.Article {
+	if s.embedder == nil || s.cfg.KBArticles <= 0 {
+		return nil
+	}
+	entries := s.articleEntries()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	vectors, err := s.embedder.Embed(ctx, []string{text})
+	cancel()
+	if err != nil {
+		log.Printf("Failed to embed ticket for article retrieval: %v", err)
+		return nil
+	}
+
+	type scored struct {
+		article models.Article
+		score   float64
+	}
+	var matches []scored
+	for _, e := range entries {
+		if score := embedding.Cosine(vectors[0], e.vector); score >= s.cfg.KBMinScore {
+			matches = append(matches, scored{e.article, score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	var articles []models.Article
+	for i := 0; i < len(matches) && i < s.cfg.KBArticles; i++ {
+		articles = append(articles, matches[i].article)
+	}
+	return articles
+}
+
+// describeArticles renders retrieved articles as a prompt section. Each
+// body gets an equal share of the token budget.
+func describeArticles(articles []models.Article, maxTokens int) string {
+	if len(articles) == 0 {
+		return "Knowledge base articles: none match this ticket."
+	}
+	var sb strings.Builder
+	sb.WriteString("Knowledge base articles (cite by id when the suggestion relies on one):\n")
+	for _, a := range articles {
+		fmt.Fprintf(&sb, "- id %s: %s\n  %s\n", a.ID, a.Title, truncateTokens(strings.ReplaceAll(a.Body, "\n", " "), maxTokens/len(articles)))
+	}
+	return sb.String()
+}
+
+// citedArticles keeps only the ids the model cited that were actually
+// offered, in the order given
+func citedArticles(cited []string, offered []models.Article) []uuid.UUID {
+	known := map[uuid.UUID]bool{}
+	for _, a := range offered {
+		known[a.ID] = true
+	}
+	var ids []uuid.UUID
+	for _, c := range cited {
+		id, err := uuid.Parse(strings.TrimSpace(c))
+		if err == nil && known[id] {
+			ids = append(ids, id)
+			delete(known, id) // No repeats
+		}
+	}
+	return ids
+}
@@ -0,0 +1,187 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type geminiProvider struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+func NewGeminiProvider(apiKey, model string, timeout time.Duration) Provider {
+	return &geminiProvider{apiKey: apiKey, model: model, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *geminiProvider) Name() string  { return "gemini" }
+func (p *geminiProvider) Model() string { return p.model }
+
+// Gemini enforces responseSchema when responseMimeType is application/json
+func (p *geminiProvider) SupportsStructuredOutput() bool { return true }
+
+// geminiResponse is a generateContent answer, or one chunk of a streamed one
+type geminiResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+	ModelVersion string `json:"modelVersion"`
+}
+
+func (r *geminiResponse) text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range r.Candidates[0].Content.Parts {
+		sb.WriteString(part.Text)
+	}
+	return sb.String()
+}
+
+// post sends r to the given model method, asking for server-sent events when
+// streaming, and returns the response once its status is 200
+func (p *geminiProvider) post(ctx context.Context, r Request, method string, stream bool) (*http.Response, error) {
+	generationConfig := map[string]interface{}{
+		"temperature":     r.Temperature,
+		"maxOutputTokens": r.MaxOutputTokens,
+	}
+	if r.Schema != nil {
+		generationConfig["responseMimeType"] = "application/json"
+		generationConfig["responseSchema"] = r.Schema
+	}
+	payload := map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{
+				"parts": []map[string]interface{}{
+					{"text": r.Prompt},
+				},
+			},
+		},
+		"generationConfig": generationConfig,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	url := "https://generativelanguage.googleapis.com/v1beta/models/" + p.model + ":" + method
+	if stream {
+		url += "?alt=sse"
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// In a header rather than ?key=, so it stays out of errors and logs that quote the URL
+	req.Header.Set("x-goog-api-key", p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Gemini API call failed: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{
+			Provider:   "Gemini",
+			Code:       resp.StatusCode,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	return resp, nil
+}
+
+func (p *geminiProvider) Generate(ctx context.Context, r Request) (*Response, error) {
+	resp, err := p.post(ctx, r, "generateContent", false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var response geminiResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse Gemini response: %w", err)
+	}
+	text := response.text()
+	if text == "" {
+		log.Printf("No text in Gemini response: %s", string(body))
+		return nil, ErrEmptyResponse
+	}
+	return p.response(text, &response), nil
+}
+
+// Stream uses streamGenerateContent, which sends server-sent events with
+// one partial response each; usage comes with the last one
+func (p *geminiProvider) Stream(ctx context.Context, r Request, fn func(chunk string) error) (*Response, error) {
+	resp, err := p.post(ctx, r, "streamGenerateContent", true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var sb strings.Builder
+	var last geminiResponse
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var chunk geminiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("failed to parse Gemini stream: %w", err)
+		}
+		if text := chunk.text(); text != "" {
+			sb.WriteString(text)
+			if err := fn(text); err != nil {
+				return nil, err
+			}
+		}
+		last = chunk
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Gemini stream failed: %w", err)
+	}
+	if sb.Len() == 0 {
+		return nil, ErrEmptyResponse
+	}
+	return p.response(sb.String(), &last), nil
+}
+
+func (p *geminiProvider) response(text string, r *geminiResponse) *Response {
+	model := r.ModelVersion
+	if model == "" {
+		model = p.model
+	}
+	return &Response{
+		Text:         text,
+		Model:        model,
+		InputTokens:  r.UsageMetadata.PromptTokenCount,
+		OutputTokens: r.UsageMetadata.CandidatesTokenCount,
+	}
+}
@@ -0,0 +1,230 @@
+package llm
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// maxBackoff caps the wait between retries when the provider doesn't say
+const maxBackoff = 30 * time.Second
+
+// guardedProvider protects a provider and our bill: a token-bucket rate
+// limit, a cap on requests in flight, retries of transient failures that
+// honor Retry-After, and a circuit breaker that fails fast during outages.
+type guardedProvider struct {
+	Provider
+	limiter *tokenBucket
+	slots   chan struct{}
+	retries int
+	backoff time.Duration
+	breaker *breaker
+}
+
+// NewGuardedProvider wraps p with the limits in cfg. Calls that can't be
+// made return an error wrapping ErrUnavailable.
+func NewGuardedProvider(p Provider, cfg config.AIConfig) Provider {
+	concurrent := cfg.MaxConcurrent
+	if concurrent < 1 {
+		concurrent = 1
+	}
+	return &guardedProvider{
+		Provider: p,
+		limiter:  newTokenBucket(cfg.RateLimit, cfg.RateBurst),
+		slots:    make(chan struct{}, concurrent),
+		retries:  cfg.MaxRetries,
+		backoff:  cfg.RetryBackoff,
+		breaker:  &breaker{threshold: cfg.BreakerThreshold, cooldown: cfg.BreakerCooldown},
+	}
+}
+
+func (g *guardedProvider) Generate(ctx context.Context, req Request) (*Response, error) {
+	return g.do(ctx, func(ctx context.Context) (*Response, error) {
+		return g.Provider.Generate(ctx, req)
+	}, nil)
+}
+
+// Stream applies the same limits. A stream that fails after sending text
+// isn't retried, since the caller already has part of the answer.
+func (g *guardedProvider) Stream(ctx context.Context, req Request, fn func(chunk string) error) (*Response, error) {
+	streamed := false
+	return g.do(ctx, func(ctx context.Context) (*Response, error) {
+		return Stream(ctx, g.Provider, req, func(chunk string) error {
+			streamed = true
+			return fn(chunk)
+		})
+	}, func() bool { return !streamed })
+}
+
+// do makes the call through the breaker, rate limiter and retries;
+// canRetry, when given, can veto a retry
+func (g *guardedProvider) do(ctx context.Context, call func(context.Context) (*Response, error), canRetry func() bool) (*Response, error) {
+	if !g.breaker.allow() {
+		return nil, fmt.Errorf("%w: circuit open after repeated %s failures", ErrUnavailable, g.Name())
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= g.retries; attempt++ {
+		if attempt > 0 {
+			wait := g.retryDelay(attempt, lastErr)
+			log.Printf("Retrying %s call in %s (attempt %d/%d): %v", g.Name(), wait, attempt, g.retries, lastErr)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				g.breaker.record(false)
+				return nil, fmt.Errorf("%w: %v", ErrUnavailable, lastErr)
+			}
+		}
+
+		resp, err := g.call(ctx, call)
+		if err == nil || !transient(err) {
+			// A bad request or unusable answer says nothing about the provider's health
+			g.breaker.record(true)
+			return resp, err
+		}
+		lastErr = err
+		if ctx.Err() != nil || (canRetry != nil && !canRetry()) {
+			break
+		}
+	}
+	g.breaker.record(false)
+	return nil, fmt.Errorf("%w: %v", ErrUnavailable, lastErr)
+}
+
+// call waits for the rate limiter and a free slot, then makes one request
+func (g *guardedProvider) call(ctx context.Context, call func(context.Context) (*Response, error)) (*Response, error) {
+	if err := g.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	select {
+	case g.slots <- struct{}{}:
+		defer func() { <-g.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return call(ctx)
+}
+
+// retryDelay honors Retry-After and otherwise backs off exponentially
+func (g *guardedProvider) retryDelay(attempt int, err error) time.Duration {
+	var se *StatusError
+	if errors.As(err, &se) && se.RetryAfter > 0 {
+		return se.RetryAfter
+	}
+	wait := g.backoff << (attempt - 1)
+	if wait <= 0 || wait > maxBackoff {
+		wait = maxBackoff
+	}
+	return wait
+}
+
+// transient reports whether err is worth retrying: rate limits, server
+// errors, timeouts and network failures
+func transient(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Temporary()
+	}
+	var ne net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &ne)
+}
+
+// tokenBucket allows rate requests per second on average, in bursts of up to burst
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait blocks until a token is available; a rate of 0 disables the limit
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// breaker opens after threshold consecutive failed calls. Once cooldown has
+// passed it lets a single trial call through (half-open); success closes it.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	trial     bool
+}
+
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.trial || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *breaker) record(ok bool) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if ok {
+		if b.failures >= b.threshold {
+			log.Printf("LLM circuit breaker closed")
+		}
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			log.Printf("LLM circuit breaker opened after %d consecutive failures", b.failures)
+		}
+		b.openedAt = time.Now()
+	}
+}
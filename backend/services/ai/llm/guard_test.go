@@ -0,0 +1,189 @@
+package llm
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// flakyProvider fails with errs in turn, then answers
+type flakyProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *flakyProvider) Name() string                   { return "flaky" }
+func (p *flakyProvider) Model() string                  { return "flaky-1" }
+func (p *flakyProvider) SupportsStructuredOutput() bool { return false }
+
+func (p *flakyProvider) Generate(ctx context.Context, req Request) (*Response, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	return &Response{Text: "ok"}, nil
+}
+
+func unavailable(code int) error { return &StatusError{Provider: "flaky", Code: code} }
+
+func TestGuardedProviderRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error // Nil for an answer
+	}{
+		{"no failure", nil, 1, nil},
+		{"server error retried", []error{unavailable(http.StatusServiceUnavailable)}, 2, nil},
+		{"rate limit retried", []error{&StatusError{Provider: "flaky", Code: http.StatusTooManyRequests, RetryAfter: time.Millisecond}}, 2, nil},
+		{"timeout retried", []error{context.DeadlineExceeded}, 2, nil},
+		{"bad request not retried", []error{unavailable(http.StatusBadRequest)}, 1, &StatusError{}},
+		{"empty answer not retried", []error{ErrEmptyResponse}, 1, ErrEmptyResponse},
+		{
+			name:      "retries exhausted",
+			errs:      []error{unavailable(500), unavailable(502), unavailable(503)},
+			wantCalls: 3,
+			wantErr:   ErrUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &flakyProvider{errs: tt.errs}
+			g := NewGuardedProvider(p, config.AIConfig{MaxRetries: 2, RetryBackoff: time.Millisecond})
+			resp, err := g.Generate(context.Background(), Request{})
+			if p.calls != tt.wantCalls {
+				t.Errorf("%d calls, want %d", p.calls, tt.wantCalls)
+			}
+			var se *StatusError
+			switch target := tt.wantErr.(type) {
+			case nil:
+				if err != nil || resp.Text != "ok" {
+					t.Errorf("Generate = %v, %v, want the answer", resp, err)
+				}
+			case *StatusError:
+				if !errors.As(err, &se) || errors.Is(err, ErrUnavailable) {
+					t.Errorf("Generate error = %v, want the provider's error as is", err)
+				}
+			default:
+				if !errors.Is(err, target) {
+					t.Errorf("Generate error = %v, want %v", err, target)
+				}
+			}
+		})
+	}
+}
+
+func TestGuardedProviderOpensBreaker(t *testing.T) {
+	p := &flakyProvider{errs: []error{unavailable(500), unavailable(500)}}
+	g := NewGuardedProvider(p, config.AIConfig{BreakerThreshold: 1, BreakerCooldown: time.Hour})
+	if _, err := g.Generate(context.Background(), Request{}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("first call = %v, want ErrUnavailable", err)
+	}
+	if _, err := g.Generate(context.Background(), Request{}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("call with the circuit open = %v, want ErrUnavailable", err)
+	}
+	if p.calls != 1 {
+		t.Errorf("%d calls reached the provider, want 1", p.calls)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	g := &guardedProvider{backoff: time.Second}
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{"first retry", 1, unavailable(500), time.Second},
+		{"doubles", 3, unavailable(500), 4 * time.Second},
+		{"capped", 10, unavailable(500), maxBackoff},
+		{"retry-after honored", 3, &StatusError{Code: 429, RetryAfter: 7 * time.Second}, 7 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.retryDelay(tt.attempt, tt.err); got != tt.want {
+				t.Errorf("retryDelay = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64
+		burst   int
+		calls   int
+		minWait time.Duration
+		maxWait time.Duration
+	}{
+		{"unlimited", 0, 1, 100, 0, 50 * time.Millisecond},
+		{"within the burst", 10, 5, 5, 0, 50 * time.Millisecond},
+		{"past the burst", 50, 2, 4, 35 * time.Millisecond, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTokenBucket(tt.rate, tt.burst)
+			start := time.Now()
+			for i := 0; i < tt.calls; i++ {
+				if err := b.wait(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if took := time.Since(start); took < tt.minWait || took > tt.maxWait {
+				t.Errorf("%d calls took %s, want between %s and %s", tt.calls, took, tt.minWait, tt.maxWait)
+			}
+		})
+	}
+}
+
+func TestTokenBucketCanceled(t *testing.T) {
+	b := newTokenBucket(0.001, 1)
+	if err := b.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait on an empty bucket = %v, want the context's error", err)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	// Steps: "fail" and "ok" record a call, "allow" and "deny" check allow,
+	// "cool" waits out the cooldown
+	tests := []struct {
+		name      string
+		threshold int
+		steps     []string
+	}{
+		{"disabled", 0, []string{"fail", "fail", "fail", "allow"}},
+		{"below threshold", 3, []string{"fail", "fail", "allow"}},
+		{"opens at threshold", 3, []string{"fail", "fail", "fail", "deny"}},
+		{"success resets the count", 3, []string{"fail", "fail", "ok", "fail", "fail", "allow"}},
+		{"one trial after cooldown", 2, []string{"fail", "fail", "deny", "cool", "allow", "deny"}},
+		{"trial success closes", 2, []string{"fail", "fail", "cool", "allow", "ok", "allow", "allow"}},
+		{"trial failure reopens", 2, []string{"fail", "fail", "cool", "allow", "fail", "deny"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &breaker{threshold: tt.threshold, cooldown: 20 * time.Millisecond}
+			for i, step := range tt.steps {
+				switch step {
+				case "fail", "ok":
+					b.record(step == "ok")
+				case "cool":
+					time.Sleep(25 * time.Millisecond)
+				case "allow", "deny":
+					if got := b.allow(); got != (step == "allow") {
+						t.Fatalf("step %d: allow = %t, want %t", i, got, step == "allow")
+					}
+				}
+			}
+		})
+	}
+}
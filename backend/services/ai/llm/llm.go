@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrEmptyResponse means the provider answered but returned no usable text
+var ErrEmptyResponse = errors.New("empty LLM response")
+
+// ErrUnavailable means the provider can't be used right now (outage, rate
+// limits, open circuit breaker); the request should be tried again later
+var ErrUnavailable = errors.New("LLM provider unavailable")
+
+// StatusError is a non-200 answer from a provider's HTTP API
+type StatusError struct {
+	Provider   string
+	Code       int
+	Body       string
+	RetryAfter time.Duration // From the Retry-After header; 0 if absent
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s error %d: %s", e.Provider, e.Code, e.Body)
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e *StatusError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code == http.StatusRequestTimeout || e.Code >= 500
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// Request is a single prompt sent to a provider
+type Request struct {
+	Prompt          string
+	Schema          map[string]interface{} // JSON schema for structured output; nil for free text
+	Temperature     float64
+	MaxOutputTokens int
+}
+
+// Response is the provider's text answer plus usage for accounting
+type Response struct {
+	Text         string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+}
+
+// Provider is an LLM backend the AI service can classify with
+type Provider interface {
+	Name() string
+	Model() string
+	// SupportsStructuredOutput reports whether Request.Schema is enforced by the provider
+	SupportsStructuredOutput() bool
+	Generate(ctx context.Context, req Request) (*Response, error)
+}
+
+// Streamer is implemented by providers that can send their answer as it
+// is generated
+type Streamer interface {
+	// Stream calls fn with each piece of text; the Response holds the whole
+	// answer. An error from fn stops the stream.
+	Stream(ctx context.Context, req Request, fn func(chunk string) error) (*Response, error)
+}
+
+// Stream streams p's answer to fn, or for providers that can't stream,
+// sends the whole answer as one chunk
+func Stream(ctx context.Context, p Provider, req Request, fn func(chunk string) error) (*Response, error) {
+	if s, ok := p.(Streamer); ok {
+		return s.Stream(ctx, req, fn)
+	}
+	resp, err := p.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(resp.Text); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// New builds the provider named in cfg.Provider
+func New(cfg config.AIConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "gemini":
+		return NewGeminiProvider(cfg.GeminiAPIKey, cfg.Model, cfg.RequestTimeout), nil
+	case "replay":
+		return NewReplayProvider(cfg.ReplayFile)
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q", cfg.Provider)
+	}
+}
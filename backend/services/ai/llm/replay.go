@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Recording is one prompt/response pair in a replay file (JSON lines)
+type Recording struct {
+	Key          string `json:"key"`
+	Structured   bool   `json:"structured"`
+	Text         string `json:"text"`
+	Model        string `json:"model"`
+	InputTokens  int    `json:"input_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+}
+
+// recordingKey identifies a request by its prompt and schema, so a replay
+// only matches when the prompt that would be sent is byte-for-byte the same
+func recordingKey(req Request) string {
+	schema, _ := json.Marshal(req.Schema) // Map keys are sorted, so this is stable
+	h := sha256.New()
+	h.Write([]byte(req.Prompt))
+	h.Write([]byte{0})
+	h.Write(schema)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type replayProvider struct {
+	model      string
+	structured bool
+	recordings map[string]Recording
+}
+
+// NewReplayProvider answers from a file written by NewRecorder, without
+// network calls. Unknown prompts fail, which usually means the prompt changed.
+func NewReplayProvider(path string) (Provider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := &replayProvider{model: "replay", structured: true, recordings: map[string]Recording{}}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var rec Recording
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if len(p.recordings) == 0 {
+			p.model, p.structured = rec.Model, rec.Structured
+		}
+		p.recordings[rec.Key] = rec
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *replayProvider) Name() string  { return "replay" }
+func (p *replayProvider) Model() string { return p.model }
+
+// Replays whatever mode the recording was made with, so prompts match
+func (p *replayProvider) SupportsStructuredOutput() bool { return p.structured }
+
+func (p *replayProvider) Generate(ctx context.Context, req Request) (*Response, error) {
+	rec, ok := p.recordings[recordingKey(req)]
+	if !ok {
+		return nil, fmt.Errorf("replay: no recorded response for this prompt")
+	}
+	if rec.Text == "" {
+		return nil, ErrEmptyResponse
+	}
+	return &Response{Text: rec.Text, Model: rec.Model, InputTokens: rec.InputTokens, OutputTokens: rec.OutputTokens}, nil
+}
+
+// recorder passes requests through to another provider and appends every
+// response to a replay file
+type recorder struct {
+	Provider
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewRecorder wraps inner so its responses can be replayed later
+func NewRecorder(inner Provider, path string) (Provider, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &recorder{Provider: inner, file: f}, nil
+}
+
+func (r *recorder) Generate(ctx context.Context, req Request) (*Response, error) {
+	resp, err := r.Provider.Generate(ctx, req)
+	if err != nil && err != ErrEmptyResponse {
+		return nil, err
+	}
+	rec := Recording{Key: recordingKey(req), Structured: r.SupportsStructuredOutput(), Model: r.Model()}
+	if resp != nil {
+		rec.Text, rec.Model, rec.InputTokens, rec.OutputTokens = resp.Text, resp.Model, resp.InputTokens, resp.OutputTokens
+	}
+	line, _ := json.Marshal(rec)
+	r.mu.Lock()
+	_, werr := r.file.Write(append(line, '\n'))
+	r.mu.Unlock()
+	if werr != nil {
+		return nil, fmt.Errorf("record response: %w", werr)
+	}
+	return resp, err
+}
@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/pkg/redis"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ticketLockTTL frees the lock of an instance that stopped mid-classification.
+// It is well above what a classification takes with provider retries.
+const ticketLockTTL = 10 * time.Minute
+
+// ticketLockPoll is how often a waiting caller retries the lock
+const ticketLockPoll = 200 * time.Millisecond
+
+// ErrTicketBusy is returned to callers that don't wait for the ticket's lock
+var ErrTicketBusy = errors.New("ticket is being classified, try again shortly")
+
+func ticketLockKey(id uuid.UUID) string {
+	return "ai:lock:ticket:" + id.String()
+}
+
+// lockTicket waits until no one else is classifying the ticket, in this or
+// any other instance or in ai-backfill, and returns the function that
+// releases it. The consumer only serializes events of one ticket within an
+// instance; reclassify requests, backfills and queue retries come from
+// outside it. Without Redis, classification goes ahead unlocked.
+func (s *aiService) lockTicket(id uuid.UUID) (func(), error) {
+	if s.cache == nil {
+		return func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ticketLockTTL)
+	defer cancel()
+	for {
+		unlock, err := s.cache.Lock(ctx, ticketLockKey(id), ticketLockTTL)
+		if err == nil {
+			return unlock, nil
+		}
+		if !errors.Is(err, redis.ErrLocked) {
+			log.Printf("Failed to lock ticket %s, classifying without the lock: %v", id, err)
+			return func() {}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("ticket %s is still being classified elsewhere", id)
+		case <-time.After(ticketLockPoll):
+		}
+	}
+}
+
+// tryLockTicket is lockTicket for request handlers: it returns ErrTicketBusy
+// right away instead of waiting for the lock
+func (s *aiService) tryLockTicket(id uuid.UUID) (func(), error) {
+	if s.cache == nil {
+		return func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	unlock, err := s.cache.Lock(ctx, ticketLockKey(id), ticketLockTTL)
+	switch {
+	case err == nil:
+		return unlock, nil
+	case errors.Is(err, redis.ErrLocked):
+		return nil, ErrTicketBusy
+	}
+	log.Printf("Failed to lock ticket %s, classifying without the lock: %v", id, err)
+	return func() {}, nil
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AuthMiddleware validates JWT and sets user_id/role/tenant in context (no DB lookup)
+func AuthMiddleware(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Abort()
+			return
+		}
+
+		tokenStr := strings.Replace(authHeader, "Bearer ", "", 1)
+		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		})
+		if err != nil || !token.Valid {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+		userIDStr, ok := claims["user_id"].(string)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing user_id in token claims"})
+			c.Abort()
+			return
+		}
+		role, _ := claims["role"].(string)
+		tenant, _ := claims["tenant"].(string)
+		if tenant == "" {
+			tenant = models.DefaultTenant
+		}
+		c.Set("user_id", models.MustParseUUID(userIDStr))
+		c.Set("role", role)
+		c.Set("tenant", tenant)
+		c.Next()
+	}
+}
+
+// AgentAuthMiddleware checks for 'agent' role
+func AgentAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists || role.(string) != "agent" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Agent role required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,78 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/pkg/prompt"
+	"log"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// promptTemplate is a compiled prompt and the version label stored on runs
+type promptTemplate struct {
+	tmpl  *template.Template
+	label string
+}
+
+// promptCache holds the active templates per prompt name, keyed by tenant
+// ("" for the global version). Activations show up within promptTTL, so
+// prompts change without redeploying the AI service.
+type promptCache struct {
+	mu       sync.Mutex
+	byName   map[string]map[string]promptTemplate
+	loadedAt map[string]time.Time
+}
+
+const promptTTL = time.Minute
+
+// loadPrompt resolves the template for name: the tenant's active override,
+// else the global active version, else the embedded default.
+func (s *aiService) loadPrompt(name, tenant string) promptTemplate {
+	s.prompts.mu.Lock()
+	defer s.prompts.mu.Unlock()
+	if s.prompts.byName == nil {
+		s.prompts.byName = map[string]map[string]promptTemplate{}
+		s.prompts.loadedAt = map[string]time.Time{}
+	}
+
+	if loaded, ok := s.prompts.loadedAt[name]; !ok || time.Since(loaded) >= promptTTL {
+		active := map[string]promptTemplate{}
+		templates, err := s.repo.ListActivePrompts(name)
+		if err != nil {
+			log.Printf("Failed to load %s prompts, using cached/default: %v", name, err)
+			active = s.prompts.byName[name]
+		}
+		for _, t := range templates {
+			tmpl, err := prompt.Parse(name, t.Body)
+			if err != nil {
+				log.Printf("Skipping prompt %s: %v", prompt.Label(t.Name, t.Version, t.Tenant), err)
+				continue
+			}
+			active[t.Tenant] = promptTemplate{tmpl: tmpl, label: prompt.Label(t.Name, t.Version, t.Tenant)}
+		}
+		s.prompts.byName[name] = active
+		s.prompts.loadedAt[name] = time.Now()
+	}
+
+	active := s.prompts.byName[name]
+	if t, ok := active[tenant]; ok && tenant != "" {
+		return t
+	}
+	if t, ok := active[""]; ok {
+		return t
+	}
+	return defaultPrompt(name)
+}
+
+// defaultPrompt compiles the embedded template; these ship with the binary
+// so a failure is a programming error
+func defaultPrompt(name string) promptTemplate {
+	body, ok := prompt.Default(name)
+	if !ok {
+		panic("no embedded prompt " + name)
+	}
+	return promptTemplate{
+		tmpl:  template.Must(prompt.Parse(name, body)),
+		label: prompt.DefaultLabel(name),
+	}
+}
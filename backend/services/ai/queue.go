@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/llm"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusQueued marks a ticket waiting for the LLM to become available again
+const StatusQueued = "ai_queued"
+
+// StatusProcessing marks a queued ticket claimed by one instance's retry
+const StatusProcessing = "ai_processing"
+
+// claimLease is how long a claimed ticket may stay in ai_processing before
+// another instance assumes its claimer went away and claims it again
+const claimLease = 10 * time.Minute
+
+func isQueued(status string) bool {
+	return status == StatusQueued || status == StatusProcessing
+}
+
+// ProcessQueued claims queued tickets, oldest first, and classifies them.
+// Claiming moves them to ai_processing in one statement, so instances
+// retrying at the same time never classify the same ticket. It stops at the
+// first ticket that can't be processed because the LLM is still unavailable
+// and puts the rest back in the queue.
+func (s *aiService) ProcessQueued(limit int) (int, error) {
+	tickets, err := s.repo.ClaimTickets(StatusQueued, StatusProcessing, time.Now().Add(-claimLease), limit)
+	if err != nil {
+		return 0, err
+	}
+	processed := 0
+	for i, t := range tickets {
+		err := s.ProcessTicketContent(t.ID, t.Title, t.Description)
+		if errors.Is(err, llm.ErrUnavailable) {
+			s.releaseQueued(tickets[i+1:])
+			return processed, nil
+		}
+		if err != nil {
+			// Left claimed, so it is retried once the lease runs out
+			log.Printf("Failed to process queued ticket %s: %v", t.ID, err)
+			continue
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// QueueTicket moves a ticket still in triage to ai_queued, so the retry
+// sweep classifies it when an event about it couldn't be handled
+func (s *aiService) QueueTicket(ticketID uuid.UUID) error {
+	ticket, err := s.repo.GetByID(ticketID)
+	if err != nil {
+		return fmt.Errorf("ticket not found: %w", err)
+	}
+	if !isTriage(ticket.Status) || isQueued(ticket.Status) {
+		return nil
+	}
+	oldStatus := ticket.Status
+	ticket.Status = StatusQueued
+	applied, err := s.repo.ApplyClassification(ticket, oldStatus, nil)
+	if err != nil {
+		return fmt.Errorf("update failed: %w", err)
+	}
+	if applied {
+		s.publishStatusChange(ticket, oldStatus)
+	}
+	return nil
+}
+
+func (s *aiService) releaseQueued(tickets []models.Ticket) {
+	ids := make([]uuid.UUID, 0, len(tickets))
+	for _, t := range tickets {
+		ids = append(ids, t.ID)
+	}
+	if err := s.repo.ReleaseTickets(ids, StatusProcessing, StatusQueued); err != nil {
+		log.Printf("Failed to release %d claimed tickets: %v", len(ids), err)
+	}
+}
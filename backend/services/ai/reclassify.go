@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// TriageStatuses are the statuses a ticket can be reclassified in. Later
+// statuses belong to the agent working the ticket, and reclassifying would
+// move it back to review.
+var TriageStatuses = []string{"open", StatusQueued, StatusProcessing, "pending_review", "classified"}
+
+func isTriage(status string) bool {
+	for _, s := range TriageStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Reclassify runs the current prompt and model on the ticket's current
+// title and description, bypassing the unchanged-prompt skip and the
+// result cache, e.g. after a prompt or model change. It doesn't wait for a
+// classification of the ticket already running.
+func (s *aiService) Reclassify(caller Caller, ticketID uuid.UUID) (*models.AIClassification, error) {
+	if _, err := s.authorizeTicket(caller, ticketID); err != nil {
+		return nil, err
+	}
+	unlock, err := s.tryLockTicket(ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.reclassify(ticketID)
+}
+
+func (s *aiService) Backfill(ticketID uuid.UUID) (*models.AIClassification, error) {
+	// Waits for the consumer or a backfill classifying the same ticket
+	unlock, err := s.lockTicket(ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return s.reclassify(ticketID)
+}
+
+// reclassify forces a classification; the caller holds the ticket's lock
+func (s *aiService) reclassify(ticketID uuid.UUID) (*models.AIClassification, error) {
+	ticket, err := s.repo.GetByID(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket not found: %w", err)
+	}
+	if !isTriage(ticket.Status) {
+		return nil, fmt.Errorf("invalid request: ticket is %s, only tickets still in triage can be reclassified", ticket.Status)
+	}
+	return s.classifyTicket(ticket, ticket.Title, ticket.Description, true)
+}
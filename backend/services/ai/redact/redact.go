@@ -0,0 +1,275 @@
+// Package redact masks personal data and secrets in text before it is sent
+// to an LLM, and puts it back into the model's answer where that is safe.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of data a Redactor can detect
+const (
+	Card     = "card"
+	IBAN     = "iban"
+	Email    = "email"
+	Phone    = "phone"
+	APIKey   = "api_key"
+	Password = "password"
+)
+
+// Kinds lists every detector, in the order they run. Secrets go first so
+// their digits aren't taken for phone or card numbers.
+var Kinds = []string{APIKey, Password, Email, IBAN, Card, Phone}
+
+type detector struct {
+	re    *regexp.Regexp
+	group int                     // Submatch holding the value; 0 for the whole match
+	valid func(value string) bool // Checksum or shape check; nil accepts every match
+}
+
+var detectors = map[string]detector{
+	APIKey: {re: regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_-]{20,}|[sr]k_(?:live|test)_[A-Za-z0-9]{16,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{35}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9-]{10,}|eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`)},
+	Password: {
+		re:    regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|passcode)(?:\s*[:=]\s*|\s+is\s+)("[^"\n]+"|'[^'\n]+'|[^\s"']*[^\s"'.,;:!?)])`),
+		group: 1,
+	},
+	Email: {re: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)},
+	IBAN:  {re: regexp.MustCompile(`(?i)\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`), valid: validIBAN},
+	Card:  {re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: validCard},
+	// A leading +, ( or 0, or separators between the groups, so that plain
+	// order and ticket numbers are left alone
+	Phone: {re: regexp.MustCompile(`(?:\+|\(|\b0)\d[\d ().-]{6,}\d\b|\b\d{2,4}[ .-]\d{2,4}[ .-]\d{2,5}(?:[ .-]\d{2,5})?\b`), valid: validPhone},
+}
+
+// Known reports whether kind has a detector
+func Known(kind string) bool {
+	_, ok := detectors[kind]
+	return ok
+}
+
+// Redactor detects the configured kinds of data
+type Redactor struct {
+	kinds []string
+}
+
+// New returns a Redactor for kinds, in their fixed detection order. Unknown
+// kinds are an error.
+func New(kinds []string) (*Redactor, error) {
+	wanted := map[string]bool{}
+	for _, k := range kinds {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		if !Known(k) {
+			return nil, fmt.Errorf("unknown redaction kind %q (known: %s)", k, strings.Join(Kinds, ", "))
+		}
+		wanted[k] = true
+	}
+	r := &Redactor{}
+	for _, k := range Kinds {
+		if wanted[k] {
+			r.kinds = append(r.kinds, k)
+		}
+	}
+	return r, nil
+}
+
+// Mapping redacts the texts of one request, so the same value always gets
+// the same placeholder, and restores the placeholders afterwards. A nil
+// Mapping leaves text unchanged.
+type Mapping struct {
+	r       *Redactor
+	byValue map[string]string // value -> placeholder
+	values  map[string]value  // placeholder -> value
+	counts  map[string]int
+}
+
+type value struct {
+	kind string
+	text string
+}
+
+// Mapping starts a new mapping; nil when no kinds are configured
+func (r *Redactor) Mapping() *Mapping {
+	if r == nil || len(r.kinds) == 0 {
+		return nil
+	}
+	return &Mapping{r: r, byValue: map[string]string{}, values: map[string]value{}, counts: map[string]int{}}
+}
+
+// Redact replaces detected values with placeholders such as [EMAIL_1]
+func (m *Mapping) Redact(text string) string {
+	if m == nil || text == "" {
+		return text
+	}
+	for _, kind := range m.r.kinds {
+		d := detectors[kind]
+		text = replaceMatches(text, d, func(v string) string { return m.placeholder(kind, v) })
+	}
+	return text
+}
+
+func replaceMatches(text string, d detector, replace func(string) string) string {
+	matches := d.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*d.group], m[2*d.group+1]
+		if start < 0 || start < last {
+			continue
+		}
+		v := text[start:end]
+		if d.valid != nil && !d.valid(v) {
+			continue
+		}
+		sb.WriteString(text[last:start])
+		sb.WriteString(replace(v))
+		last = end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+func (m *Mapping) placeholder(kind, v string) string {
+	key := kind + "\x00" + v
+	if p, ok := m.byValue[key]; ok {
+		return p
+	}
+	m.counts[kind]++
+	p := fmt.Sprintf("[%s_%d]", strings.ToUpper(kind), m.counts[kind])
+	m.byValue[key] = p
+	m.values[p] = value{kind: kind, text: v}
+	return p
+}
+
+var placeholderRe = regexp.MustCompile(`\[(?:CARD|IBAN|EMAIL|PHONE|API_KEY|PASSWORD)_\d+\]`)
+
+// Restore puts values back into text meant for people: contact details in
+// full, card numbers and IBANs masked to their last digits, and secrets
+// not at all
+func (m *Mapping) Restore(text string) string {
+	return m.restore(text, false)
+}
+
+// RestoreAll puts every value back verbatim, for output that must match
+// the input, such as translations
+func (m *Mapping) RestoreAll(text string) string {
+	return m.restore(text, true)
+}
+
+func (m *Mapping) restore(text string, all bool) string {
+	if m == nil || len(m.values) == 0 {
+		return text
+	}
+	return placeholderRe.ReplaceAllStringFunc(text, func(p string) string {
+		v, ok := m.values[p]
+		if !ok {
+			return p
+		}
+		if all {
+			return v.text
+		}
+		switch v.kind {
+		case Email, Phone:
+			return v.text
+		case Card:
+			return "**** " + lastDigits(v.text, 4)
+		case IBAN:
+			compact := strings.ToUpper(strings.ReplaceAll(v.text, " ", ""))
+			return compact[:2] + "** **** " + compact[len(compact)-4:]
+		default:
+			return "[redacted]"
+		}
+	})
+}
+
+// Summary describes what was redacted, without the values, e.g. "2 email, 1 card"
+func (m *Mapping) Summary() string {
+	if m == nil || len(m.counts) == 0 {
+		return ""
+	}
+	kinds := make([]string, 0, len(m.counts))
+	for k := range m.counts {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, len(kinds))
+	for i, k := range kinds {
+		parts[i] = fmt.Sprintf("%d %s", m.counts[k], k)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func lastDigits(s string, n int) string {
+	var digits []byte
+	for i := len(s) - 1; i >= 0 && len(digits) < n; i-- {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append([]byte{s[i]}, digits...)
+		}
+	}
+	return string(digits)
+}
+
+func digitsOf(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// validCard applies the Luhn checksum
+func validCard(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validIBAN applies the ISO 13616 mod-97 check
+func validIBAN(s string) bool {
+	iban := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	rem := 0
+	for _, c := range rearranged {
+		switch {
+		case c >= '0' && c <= '9':
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+// validPhone accepts 8 to 15 digits (E.164) that aren't a date
+func validPhone(s string) bool {
+	n := len(digitsOf(s))
+	return n >= 8 && n <= 15 && !dateRe.MatchString(strings.TrimSpace(s))
+}
+
+var dateRe = regexp.MustCompile(`^\d{1,4}[./-]\d{1,2}[./-]\d{1,4}$`)
@@ -0,0 +1,143 @@
+package redact
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"slices"
+	"testing"
+)
+
+// config.Validate checks kind names against its own list, which has to
+// match the detectors
+func TestConfigKnowsEveryKind(t *testing.T) {
+	got := slices.Sorted(slices.Values(config.RedactionKinds))
+	want := slices.Sorted(slices.Values(Kinds))
+	if !slices.Equal(got, want) {
+		t.Errorf("config.RedactionKinds = %v, want %v", got, want)
+	}
+}
+
+func TestValidCard(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"4111 1111 1111 1111", true},
+		{"4242424242424242", true},
+		{"5500-0000-0000-0004", true},
+		{"378282246310005", true}, // Amex, 15 digits
+		{"4111 1111 1111 1112", false},
+		{"4242424242424241", false},
+		{"411111111111", false},         // 12 digits
+		{"41111111111111111111", false}, // 20 digits
+	}
+	for _, tt := range tests {
+		if got := validCard(tt.in); got != tt.want {
+			t.Errorf("validCard(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidIBAN(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"DE89 3704 0044 0532 0130 00", true},
+		{"GB82 WEST 1234 5698 7654 32", true},
+		{"NL91ABNA0417164300", true},
+		{"de89370400440532013000", true},
+		{"DE89 3704 0044 0532 0130 01", false},
+		{"GB82 WEST 1234 5698 7654 33", false},
+		{"DE89 3704", false},
+		{"DE89-3704-0044-0532-0130-00", false},
+	}
+	for _, tt := range tests {
+		if got := validIBAN(tt.in); got != tt.want {
+			t.Errorf("validIBAN(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"email", "Mail jane.doe@example.com please", "Mail [EMAIL_1] please"},
+		{"card", "Card 4111 1111 1111 1111 was charged", "Card [CARD_1] was charged"},
+		{"card failing Luhn", "Reference 4111 1111 1111 1112", "Reference 4111 1111 1111 1112"},
+		{"iban", "Refund to DE89 3704 0044 0532 0130 00.", "Refund to [IBAN_1]."},
+		{"iban failing mod-97", "Refund to DE89 3704 0044 0532 0130 02.", "Refund to DE89 3704 0044 0532 0130 02."},
+		{"phone", "Call +49 30 1234567 today", "Call [PHONE_1] today"},
+		{"date is not a phone", "Since 2024-01-15", "Since 2024-01-15"},
+		{"order number", "Order 12345678 is late", "Order 12345678 is late"},
+		{"password", "my password: hunter2!", "my password: [PASSWORD_1]!"},
+		{"api key", "key sk-abcdefghijklmnopqrstuvwx leaked", "key [API_KEY_1] leaked"},
+		{"same value, same placeholder", "a@example.com wrote to b@example.com, cc a@example.com", "[EMAIL_1] wrote to [EMAIL_2], cc [EMAIL_1]"},
+	}
+	r, err := New(Kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Mapping().Redact(tt.in); got != tt.want {
+				t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	r, err := New(Kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Mapping()
+	in := "jane@example.com, 4111 1111 1111 1111, DE89 3704 0044 0532 0130 00, password: hunter2"
+	redacted := m.Redact(in)
+
+	tests := []struct {
+		name string
+		all  bool
+		in   string
+		want string
+	}{
+		{"contact details in full", false, "Hi [EMAIL_1]", "Hi jane@example.com"},
+		{"card masked", false, "Card [CARD_1]", "Card **** 1111"},
+		{"iban masked", false, "IBAN [IBAN_1]", "IBAN DE** **** 3000"},
+		{"secret dropped", false, "It was [PASSWORD_1]", "It was [redacted]"},
+		{"unknown placeholder kept", false, "See [EMAIL_9]", "See [EMAIL_9]"},
+		{"all verbatim", true, redacted, in},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Restore(tt.in)
+			if tt.all {
+				got = m.RestoreAll(tt.in)
+			}
+			if got != tt.want {
+				t.Errorf("restore(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilMapping(t *testing.T) {
+	r, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Mapping()
+	if m != nil {
+		t.Fatal("Mapping() with no kinds is not nil")
+	}
+	text := "jane@example.com [EMAIL_1]"
+	if got := m.Redact(text); got != text {
+		t.Errorf("Redact = %q, want %q", got, text)
+	}
+	if got := m.Restore(text); got != text {
+		t.Errorf("Restore = %q, want %q", got, text)
+	}
+}
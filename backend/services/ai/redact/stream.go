@@ -0,0 +1,46 @@
+package redact
+
+import "strings"
+
+// maxPlaceholder is longer than any placeholder we generate
+const maxPlaceholder = 24
+
+// Restorer restores placeholders in streamed text. A placeholder can be
+// split across chunks, so text from an unclosed "[" is held back until the
+// next chunk or Flush.
+type Restorer struct {
+	m       *Mapping
+	fn      func(chunk string) error
+	pending string
+}
+
+// Restorer wraps fn so chunks are restored before being passed on
+func (m *Mapping) Restorer(fn func(chunk string) error) *Restorer {
+	return &Restorer{m: m, fn: fn}
+}
+
+// Write restores chunk and passes on everything that can't be the start of
+// a placeholder
+func (r *Restorer) Write(chunk string) error {
+	text := r.pending + chunk
+	r.pending = ""
+	if r.m != nil {
+		if i := strings.LastIndex(text, "["); i >= 0 && !strings.Contains(text[i:], "]") && len(text)-i < maxPlaceholder {
+			text, r.pending = text[:i], text[i:]
+		}
+	}
+	if text == "" {
+		return nil
+	}
+	return r.fn(r.m.Restore(text))
+}
+
+// Flush passes on any text still held back
+func (r *Restorer) Flush() error {
+	if r.pending == "" {
+		return nil
+	}
+	text := r.pending
+	r.pending = ""
+	return r.fn(r.m.Restore(text))
+}
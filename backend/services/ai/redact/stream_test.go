@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+// restoreChunks streams chunks through a Restorer and returns what came out
+func restoreChunks(t *testing.T, m *Mapping, chunks []string) string {
+	t.Helper()
+	var out strings.Builder
+	r := m.Restorer(func(chunk string) error {
+		out.WriteString(chunk)
+		return nil
+	})
+	for _, c := range chunks {
+		if err := r.Write(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestRestorerAcrossChunks(t *testing.T) {
+	r, err := New(Kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Mapping()
+	m.Redact("jane@example.com paid with 4111 1111 1111 1111")
+
+	tests := []struct {
+		name   string
+		chunks []string
+		want   string
+	}{
+		{"whole", []string{"Hi [EMAIL_1], card [CARD_1]."}, "Hi jane@example.com, card **** 1111."},
+		{"split inside a placeholder", []string{"Hi [EMA", "IL_1], card [CARD_", "1]."}, "Hi jane@example.com, card **** 1111."},
+		{"split after the bracket", []string{"Hi [", "EMAIL_1]"}, "Hi jane@example.com"},
+		{"split before the closing bracket", []string{"Hi [EMAIL_1", "]!"}, "Hi jane@example.com!"},
+		{"placeholder spread over many chunks", []string{"[", "E", "M", "A", "I", "L", "_", "1", "]"}, "jane@example.com"},
+		{"bracket that isn't a placeholder", []string{"items[0", "] ok"}, "items[0] ok"},
+		{"unclosed bracket at the end", []string{"see [EMAIL_"}, "see [EMAIL_"},
+		{"long bracketed text isn't held back", []string{"[this is not a placeholder at all", "]"}, "[this is not a placeholder at all]"},
+		{"empty chunks", []string{"", "Hi [EMAIL_1]", ""}, "Hi jane@example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := restoreChunks(t, m, tt.chunks); got != tt.want {
+				t.Errorf("chunks %q restored to %q, want %q", tt.chunks, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestorerEverySplit(t *testing.T) {
+	r, err := New(Kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Mapping()
+	m.Redact("jane@example.com paid with 4111 1111 1111 1111")
+	text := "Contact [EMAIL_1] about [CARD_1] [x]."
+	want := m.Restore(text)
+	for i := 0; i <= len(text); i++ {
+		for j := i; j <= len(text); j++ {
+			chunks := []string{text[:i], text[i:j], text[j:]}
+			if got := restoreChunks(t, m, chunks); got != want {
+				t.Fatalf("chunks %q restored to %q, want %q", chunks, got, want)
+			}
+		}
+	}
+}
+
+func TestRestorerNilMapping(t *testing.T) {
+	var m *Mapping
+	chunks := []string{"Hi [EMA", "IL_1]"}
+	if got := restoreChunks(t, m, chunks); got != "Hi [EMAIL_1]" {
+		t.Errorf("nil mapping restored %q to %q", chunks, got)
+	}
+}
@@ -0,0 +1,47 @@
+package ai
+
+import (
+	"ai-ticketing-backend/services/ai/redact"
+	"log"
+	"sync"
+)
+
+// redactors caches the Redactor for each tenant's configured kinds
+type redactors struct {
+	mu       sync.Mutex
+	byTenant map[string]*redact.Redactor
+}
+
+// redaction starts masking the texts of one request for tenant; nil when
+// the tenant has redaction turned off
+func (s *aiService) redaction(tenant string) *redact.Mapping {
+	s.redactor.mu.Lock()
+	defer s.redactor.mu.Unlock()
+	r, ok := s.redactor.byTenant[tenant]
+	if !ok {
+		r = newRedactor(tenant, s.cfg.Redaction(tenant))
+		if s.redactor.byTenant == nil {
+			s.redactor.byTenant = map[string]*redact.Redactor{}
+		}
+		s.redactor.byTenant[tenant] = r
+	}
+	return r.Mapping()
+}
+
+// newRedactor builds the tenant's Redactor. config.Validate rejects unknown
+// kinds at startup; should one get through anyway, everything is masked.
+func newRedactor(tenant string, kinds []string) *redact.Redactor {
+	r, err := redact.New(kinds)
+	if err != nil {
+		log.Printf("Redacting every kind for tenant %q: %v", tenant, err)
+		r, _ = redact.New(redact.Kinds)
+	}
+	return r
+}
+
+// logRedaction records what was masked, never the values
+func logRedaction(m *redact.Mapping, what string) {
+	if summary := m.Summary(); summary != "" {
+		log.Printf("Redacted %s from %s before the LLM", summary, what)
+	}
+}
@@ -3,13 +3,57 @@ package repository
 import (
 	"ai-ticketing-backend/internal/models"
 	"ai-ticketing-backend/internal/pkg/db"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TicketRepository interface {
 	GetByID(id uuid.UUID) (*models.Ticket, error) // Fetch for update
-	Update(ticket *models.Ticket) error
+	// ApplyClassification writes the AI-owned columns unless the ticket's
+	// status changed since it was read; with an answer, the ticket must also
+	// still be unassigned. Reports whether the ticket was updated.
+	ApplyClassification(ticket *models.Ticket, fromStatus string, answer *models.Comment) (bool, error)
+	ListComments(ticketID uuid.UUID) ([]models.Comment, error)
+	ListAttachments(ticketID uuid.UUID) ([]models.Attachment, error)
+	ListRecentByUser(userID, excludeID uuid.UUID, limit int) ([]models.Ticket, error) // Customer history for context
+	ListCategories() ([]models.Category, error)                                       // Active only
+	ListPriorities() ([]models.Priority, error)                                       // Active only, by rank
+	CreateClassification(run *models.AIClassification) error
+	LatestClassification(ticketID uuid.UUID) (*models.AIClassification, error) // nil if never classified
+	ListActivePrompts(name string) ([]models.PromptTemplate, error)            // Global and per-tenant
+	SaveEmbedding(e *models.TicketEmbedding) error
+	ListEmbeddings(model string, since time.Time) ([]models.TicketEmbedding, error)
+	ReplaceSimilar(ticketID uuid.UUID, similar []models.TicketSimilarity) error
+	ListPublishedArticles() ([]models.Article, error)
+	CreateDraft(draft *models.ReplyDraft) error
+	GetSummary(ticketID uuid.UUID) (*models.TicketSummary, error)
+	SaveSummary(summary *models.TicketSummary) error
+	// ClaimTickets moves up to limit tickets, oldest first, from one status
+	// to another, also taking back tickets left in the target status since
+	// before staleBefore. Each ticket is claimed by one caller only.
+	ClaimTickets(from, to string, staleBefore time.Time, limit int) ([]models.Ticket, error)
+	ReleaseTickets(ids []uuid.UUID, from, to string) error // Only tickets still in from
+	FindTickets(filter TicketFilter) ([]models.Ticket, error)
+	ListTicketsByID(ids []uuid.UUID) ([]models.Ticket, error)
+	GetUsage(tenant, day string) (*models.AIUsage, error) // nil if nothing was used that day
+	AddUsage(usage *models.AIUsage) error                 // Adds to the day's totals
+	CreateConversation(conversation *models.CopilotConversation) error
+	GetConversation(id uuid.UUID) (*models.CopilotConversation, error) // With its messages, oldest first
+	AddCopilotMessage(message *models.CopilotMessage) error
+}
+
+// TicketFilter selects tickets for bulk reclassification; zero fields match any ticket
+type TicketFilter struct {
+	UserID   uuid.UUID // The customer's tickets only
+	Statuses []string
+	Category string
+	From     time.Time // Created at or after
+	To       time.Time // Created before
+	Limit    int
+	Newest   bool // Newest first, so Limit keeps the latest tickets
 }
 
 type ticketRepository struct {
@@ -29,6 +73,235 @@ func (r *ticketRepository) GetByID(id uuid.UUID) (*models.Ticket, error) {
 	return &ticket, nil
 }
 
-func (r *ticketRepository) Update(ticket *models.Ticket) error {
-	return r.db.Save(ticket).Error
+// aiColumns are the ticket columns the AI service owns; assignment, content
+// and anything else agents or customers change is never written back
+var aiColumns = []string{
+	"status", "category", "priority", "suggestion", "ai_confidence", "sentiment", "urgency", "language",
+	"translated_title", "translated_description", "escalated_at", "auto_resolved_at", "pending_customer_since", "duplicate_of",
+	"article_ids", "updated_at",
+}
+
+func (r *ticketRepository) ApplyClassification(ticket *models.Ticket, fromStatus string, answer *models.Comment) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Model(ticket).Where("status = ?", fromStatus)
+		if answer != nil {
+			q = q.Where("agent_id IS NULL AND auto_resolved_at IS NULL")
+		}
+		res := q.Select(aiColumns).Updates(ticket)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		applied = true
+		if answer != nil {
+			return tx.Create(answer).Error
+		}
+		return nil
+	})
+	return applied && err == nil, err
+}
+
+func (r *ticketRepository) ListComments(ticketID uuid.UUID) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("ticket_id = ?", ticketID).Order("created_at").Find(&comments).Error
+	return comments, err
+}
+
+func (r *ticketRepository) ListAttachments(ticketID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("ticket_id = ?", ticketID).Order("created_at").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *ticketRepository) ListRecentByUser(userID, excludeID uuid.UUID, limit int) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	err := r.db.Where("user_id = ? AND id <> ?", userID, excludeID).
+		Order("created_at DESC").Limit(limit).Find(&tickets).Error
+	return tickets, err
+}
+
+func (r *ticketRepository) ListCategories() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Where("active = ?", true).Order("name").Find(&categories).Error
+	return categories, err
+}
+
+func (r *ticketRepository) ListPriorities() ([]models.Priority, error) {
+	var priorities []models.Priority
+	err := r.db.Where("active = ?", true).Order("rank").Find(&priorities).Error
+	return priorities, err
+}
+
+// CreateClassification records the run; earlier runs still waiting for
+// review can no longer be reviewed, since their values are out of date
+func (r *ticketRepository) CreateClassification(run *models.AIClassification) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AIClassification{}).
+			Where("ticket_id = ? AND status = ? AND reviewed_at IS NULL", run.TicketID, models.ClassificationPending).
+			Update("status", models.ClassificationSuperseded).Error; err != nil {
+			return err
+		}
+		return tx.Create(run).Error
+	})
+}
+
+func (r *ticketRepository) LatestClassification(ticketID uuid.UUID) (*models.AIClassification, error) {
+	var runs []models.AIClassification
+	if err := r.db.Where("ticket_id = ?", ticketID).Order("created_at DESC").Limit(1).Find(&runs).Error; err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return &runs[0], nil
+}
+
+func (r *ticketRepository) ListActivePrompts(name string) ([]models.PromptTemplate, error) {
+	var templates []models.PromptTemplate
+	err := r.db.Where("name = ? AND status = ?", name, models.PromptActive).Find(&templates).Error
+	return templates, err
+}
+
+func (r *ticketRepository) SaveEmbedding(e *models.TicketEmbedding) error {
+	return r.db.Save(e).Error
+}
+
+func (r *ticketRepository) ListEmbeddings(model string, since time.Time) ([]models.TicketEmbedding, error) {
+	var embeddings []models.TicketEmbedding
+	err := r.db.Where("model = ? AND created_at >= ?", model, since).Find(&embeddings).Error
+	return embeddings, err
+}
+
+func (r *ticketRepository) ReplaceSimilar(ticketID uuid.UUID, similar []models.TicketSimilarity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ticket_id = ?", ticketID).Delete(&models.TicketSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(similar) == 0 {
+			return nil
+		}
+		return tx.Create(&similar).Error
+	})
+}
+
+func (r *ticketRepository) ListPublishedArticles() ([]models.Article, error) {
+	var articles []models.Article
+	err := r.db.Where("published = ?", true).Find(&articles).Error
+	return articles, err
+}
+
+func (r *ticketRepository) CreateDraft(draft *models.ReplyDraft) error {
+	return r.db.Create(draft).Error
+}
+
+func (r *ticketRepository) GetSummary(ticketID uuid.UUID) (*models.TicketSummary, error) {
+	var summary models.TicketSummary
+	if err := r.db.First(&summary, "ticket_id = ?", ticketID).Error; err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *ticketRepository) SaveSummary(summary *models.TicketSummary) error {
+	return r.db.Save(summary).Error
+}
+
+func (r *ticketRepository) ClaimTickets(from, to string, staleBefore time.Time, limit int) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	// SKIP LOCKED lets every instance claim without taking the same rows
+	err := r.db.Raw(`UPDATE tickets SET status = ?, updated_at = NOW() WHERE id IN (
+		SELECT id FROM tickets
+		WHERE status = ? OR (status = ? AND updated_at < ?)
+		ORDER BY created_at LIMIT ? FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		to, from, to, staleBefore, limit).
+		Scan(&tickets).Error
+	return tickets, err
+}
+
+func (r *ticketRepository) ReleaseTickets(ids []uuid.UUID, from, to string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Ticket{}).Where("id IN ? AND status = ?", ids, from).Update("status", to).Error
+}
+
+func (r *ticketRepository) FindTickets(filter TicketFilter) ([]models.Ticket, error) {
+	query := r.db.Model(&models.Ticket{}).Preload("User")
+	if filter.UserID != uuid.Nil {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	order := "created_at"
+	if filter.Newest {
+		order = "created_at DESC"
+	}
+	var tickets []models.Ticket
+	err := query.Order(order).Find(&tickets).Error
+	return tickets, err
+}
+
+func (r *ticketRepository) GetUsage(tenant, day string) (*models.AIUsage, error) {
+	var usage []models.AIUsage
+	if err := r.db.Where("tenant = ? AND day = ?", tenant, day).Limit(1).Find(&usage).Error; err != nil || len(usage) == 0 {
+		return nil, err
+	}
+	return &usage[0], nil
+}
+
+func (r *ticketRepository) AddUsage(usage *models.AIUsage) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"calls":         gorm.Expr("ai_usages.calls + EXCLUDED.calls"),
+			"input_tokens":  gorm.Expr("ai_usages.input_tokens + EXCLUDED.input_tokens"),
+			"output_tokens": gorm.Expr("ai_usages.output_tokens + EXCLUDED.output_tokens"),
+			"cost":          gorm.Expr("ai_usages.cost + EXCLUDED.cost"),
+			"updated_at":    gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(usage).Error
+}
+
+func (r *ticketRepository) ListTicketsByID(ids []uuid.UUID) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	if len(ids) == 0 {
+		return tickets, nil
+	}
+	err := r.db.Preload("User").Where("id IN ?", ids).Find(&tickets).Error
+	return tickets, err
+}
+
+func (r *ticketRepository) CreateConversation(conversation *models.CopilotConversation) error {
+	return r.db.Create(conversation).Error
+}
+
+func (r *ticketRepository) GetConversation(id uuid.UUID) (*models.CopilotConversation, error) {
+	var conversation models.CopilotConversation
+	err := r.db.Preload("Messages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).First(&conversation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (r *ticketRepository) AddCopilotMessage(message *models.CopilotMessage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.CopilotConversation{}).Where("id = ?", message.ConversationID).Update("updated_at", time.Now()).Error
+	})
 }
@@ -0,0 +1,32 @@
+package requeue
+
+import (
+	"ai-ticketing-backend/services/ai"
+	"log"
+	"time"
+)
+
+// batchSize bounds each pass so a long outage backlog drains gradually
+const batchSize = 50
+
+// Start retries tickets left queued while the LLM was unavailable. It runs
+// every interval, at least once a minute.
+func Start(svc ai.AIService, interval time.Duration) {
+	if interval <= 0 || interval > time.Minute {
+		interval = time.Minute
+	}
+	log.Printf("Retrying queued tickets every %s", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		processed, err := svc.ProcessQueued(batchSize)
+		if err != nil {
+			log.Printf("Requeue failed: %v", err)
+			continue
+		}
+		if processed > 0 {
+			log.Printf("Classified %d queued tickets", processed)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/embedding"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// vectorIndex is an in-process copy of recent ticket embeddings. Brute-force
+// cosine over a month of tickets is cheap; it is reloaded periodically so
+// vectors written by other AI service instances show up.
+type vectorIndex struct {
+	mu       sync.Mutex
+	entries  map[uuid.UUID]models.TicketEmbedding
+	loadedAt time.Time
+}
+
+const vectorIndexTTL = 10 * time.Minute
+
+func (s *aiService) indexEntries() map[uuid.UUID]models.TicketEmbedding {
+	if s.index.entries != nil && time.Since(s.index.loadedAt) < vectorIndexTTL {
+		return s.index.entries
+	}
+	embeddings, err := s.repo.ListEmbeddings(s.embedder.Model(), time.Now().Add(-s.cfg.SimilarityWindow))
+	if err != nil {
+		log.Printf("Failed to load embeddings, using cached index: %v", err)
+		if s.index.entries == nil {
+			s.index.entries = map[uuid.UUID]models.TicketEmbedding{}
+		}
+		return s.index.entries
+	}
+	s.index.entries = make(map[uuid.UUID]models.TicketEmbedding, len(embeddings))
+	for _, e := range embeddings {
+		s.index.entries[e.TicketID] = e
+	}
+	s.index.loadedAt = time.Now()
+	return s.index.entries
+}
+
+// detectSimilar embeds the ticket, stores the vector and records the
+// closest earlier tickets of the same tenant. A match above the duplicate
+// threshold is flagged on the ticket. Failures are logged, never fatal:
+// classification must not depend on it.
+func (s *aiService) detectSimilar(ticket *models.Ticket, title, description string) {
+	if s.embedder == nil {
+		return
+	}
+	text := title + "\n" + description
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:])
+
+	s.index.mu.Lock()
+	current, ok := s.indexEntries()[ticket.ID]
+	s.index.mu.Unlock()
+	// The embedder is a network call; other tickets shouldn't wait on it
+	if !ok || current.ContentHash != hash || current.Model != s.embedder.Model() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		vectors, err := s.embedder.Embed(ctx, []string{text})
+		cancel()
+		if err != nil {
+			log.Printf("Failed to embed ticket %s: %v", ticket.ID, err)
+			return
+		}
+		current = models.TicketEmbedding{
+			TicketID:    ticket.ID,
+			Tenant:      ticket.User.Tenant,
+			Model:       s.embedder.Model(),
+			Vector:      vectors[0],
+			ContentHash: hash,
+			CreatedAt:   ticket.CreatedAt,
+		}
+		if err := s.repo.SaveEmbedding(&current); err != nil {
+			log.Printf("Failed to save embedding for ticket %s: %v", ticket.ID, err)
+			return
+		}
+	}
+
+	s.index.mu.Lock()
+	entries := s.indexEntries()
+	entries[ticket.ID] = current
+	var similar []models.TicketSimilarity
+	for id, e := range entries {
+		// Only earlier tickets, so the newer one is always the duplicate
+		if id == ticket.ID || e.Tenant != current.Tenant || !e.CreatedAt.Before(current.CreatedAt) {
+			continue
+		}
+		score := embedding.Cosine(current.Vector, e.Vector)
+		if score < s.cfg.RelatedThreshold {
+			continue
+		}
+		kind := models.SimilarityRelated
+		if score >= s.cfg.DuplicateThreshold {
+			kind = models.SimilarityDuplicate
+		}
+		similar = append(similar, models.TicketSimilarity{TicketID: ticket.ID, SimilarTicketID: id, Score: score, Kind: kind})
+	}
+	s.index.mu.Unlock()
+	sort.Slice(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	if len(similar) > s.cfg.SimilarLimit {
+		similar = similar[:s.cfg.SimilarLimit]
+	}
+	if err := s.repo.ReplaceSimilar(ticket.ID, similar); err != nil {
+		log.Printf("Failed to save similar tickets for %s: %v", ticket.ID, err)
+		return
+	}
+
+	ticket.DuplicateOf = nil
+	if len(similar) > 0 && similar[0].Kind == models.SimilarityDuplicate {
+		ticket.DuplicateOf = &similar[0].SimilarTicketID
+		log.Printf("Ticket %s looks like a duplicate of %s (score %.2f)", ticket.ID, similar[0].SimilarTicketID, similar[0].Score)
+	}
+}
+
+// searchSimilar returns the tenant's tickets closest to text, best first,
+// scored as in detectSimilar. The text must already be redacted.
+func (s *aiService) searchSimilar(tenant, text string, exclude uuid.UUID, limit int) ([]models.TicketSimilarity, error) {
+	if s.embedder == nil {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	vectors, err := s.embedder.Embed(ctx, []string{text})
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	s.index.mu.Lock()
+	defer s.index.mu.Unlock()
+	var similar []models.TicketSimilarity
+	for id, e := range s.indexEntries() {
+		if id == exclude || e.Tenant != tenant || e.Model != s.embedder.Model() {
+			continue
+		}
+		score := embedding.Cosine(vectors[0], e.Vector)
+		if score < s.cfg.RelatedThreshold {
+			continue
+		}
+		kind := models.SimilarityRelated
+		if score >= s.cfg.DuplicateThreshold {
+			kind = models.SimilarityDuplicate
+		}
+		similar = append(similar, models.TicketSimilarity{TicketID: exclude, SimilarTicketID: id, Score: score, Kind: kind})
+	}
+	sort.Slice(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	promptpkg "ai-ticketing-backend/internal/pkg/prompt"
+	"ai-ticketing-backend/services/ai/llm"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Summarize returns the ticket's summary, regenerating it only when the
+// ticket or its thread changed since the last one
+func (s *aiService) Summarize(caller Caller, ticketID uuid.UUID) (*models.TicketSummary, error) {
+	ticket, err := s.authorizeTicket(caller, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.ListComments(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comments: %w", err)
+	}
+	return s.summarize(ticket, comments)
+}
+
+// RefreshSummary keeps the rolling summary of long threads current; short
+// tickets are left alone until an agent asks for a summary
+func (s *aiService) RefreshSummary(ticketID uuid.UUID) error {
+	ticket, err := s.repo.GetByID(ticketID)
+	if err != nil {
+		return fmt.Errorf("ticket not found: %w", err)
+	}
+	comments, err := s.repo.ListComments(ticketID)
+	if err != nil {
+		return fmt.Errorf("failed to load comments: %w", err)
+	}
+	if len(comments) < s.cfg.SummaryMinComments {
+		return nil
+	}
+	_, err = s.summarize(ticket, comments)
+	return err
+}
+
+func (s *aiService) summarize(ticket *models.Ticket, comments []models.Comment) (*models.TicketSummary, error) {
+	tmpl := s.loadPrompt("summarize", ticket.User.Tenant)
+	hash := summaryHash(ticket, comments, tmpl.label, s.cfg.AgentLanguage)
+
+	previous, err := s.repo.GetSummary(ticket.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load summary: %w", err)
+	}
+	if previous != nil && previous.ContentHash == hash {
+		log.Printf("Summary for ticket %s is current, not regenerating", ticket.ID)
+		return previous, nil
+	}
+
+	// Rolling: the previous summary stands in for the oldest messages when
+	// the whole thread no longer fits
+	previousSection := ""
+	if previous != nil && previous.Summary != "" {
+		previousSection = "\nPrevious summary (update it with what changed since):\n" + previous.Summary + "\n"
+	}
+	redaction := s.redaction(ticket.User.Tenant)
+	thread := redaction.Redact(s.describeThread(ticket, comments, s.cfg.MaxPromptTokens-estimateTokens(previousSection)))
+	logRedaction(redaction, "the thread of ticket "+ticket.ID.String())
+	prompt, err := promptpkg.Render(tmpl.tmpl, map[string]string{
+		"Ticket":   thread,
+		"Previous": redaction.Redact(previousSection),
+		"Language": languageName(s.cfg.AgentLanguage),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("render prompt %s: %w", tmpl.label, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	resp, err := s.generate(ctx, ticket.User.Tenant, llm.Request{Prompt: prompt, Temperature: 0.2, MaxOutputTokens: 800})
+	if err != nil {
+		return nil, fmt.Errorf("summary failed: %w", err)
+	}
+	text := strings.TrimSpace(redaction.Restore(resp.Text))
+	if text == "" {
+		return nil, fmt.Errorf("summary failed: %w", llm.ErrEmptyResponse)
+	}
+
+	summary := &models.TicketSummary{
+		TicketID:      ticket.ID,
+		Summary:       text,
+		Comments:      len(comments),
+		ContentHash:   hash,
+		Model:         resp.Model,
+		PromptVersion: tmpl.label,
+		InputTokens:   resp.InputTokens,
+		OutputTokens:  resp.OutputTokens,
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.repo.SaveSummary(summary); err != nil {
+		return nil, fmt.Errorf("failed to save summary: %w", err)
+	}
+	log.Printf("Summarized ticket %s (%d comments, %d/%d tokens)", ticket.ID, len(comments), resp.InputTokens, resp.OutputTokens)
+	return summary, nil
+}
+
+// describeThread renders the ticket and its comments oldest first, dropping
+// the oldest comments when the thread exceeds maxTokens
+func (s *aiService) describeThread(ticket *models.Ticket, comments []models.Comment, maxTokens int) string {
+	b := &budget{remaining: maxTokens}
+	header := fmt.Sprintf("Title: %s\nDescription: %s\n", ticket.Title, b.take(ticket.Description, 0.4))
+
+	var lines []string
+	for i := len(comments) - 1; i >= 0; i-- {
+		c := comments[i]
+		visibility := ""
+		if !c.Public {
+			visibility = " (internal note)"
+		}
+		line := fmt.Sprintf("- [%s] %s%s: %s", c.CreatedAt.Format(time.RFC3339), c.AuthorRole, visibility, c.Body)
+		if estimateTokens(line) > b.remaining {
+			lines = append(lines, fmt.Sprintf("- (%d earlier messages omitted)", i+1))
+			break
+		}
+		b.remaining -= estimateTokens(line)
+		lines = append(lines, line)
+	}
+	// Collected newest first; the model reads a thread best in order
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	if len(lines) == 0 {
+		return header
+	}
+	return header + "\nConversation:\n" + strings.Join(lines, "\n") + "\n"
+}
+
+// summaryHash covers everything the summary is generated from; status and
+// classification changes alone don't warrant a new one
+func summaryHash(ticket *models.Ticket, comments []models.Comment, promptLabel, language string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", promptLabel, language, ticket.Title, ticket.Description)
+	for _, c := range comments {
+		fmt.Fprintf(h, "%s\x00%t\x00%s\x00", c.ID, c.Public, c.Body)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
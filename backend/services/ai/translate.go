@@ -0,0 +1,145 @@
+package ai
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ai/llm"
+	"ai-ticketing-backend/services/ai/redact"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// languageNames gives the model an unambiguous target for common codes;
+// other codes are passed through as-is
+var languageNames = map[string]string{
+	"ar": "Arabic", "cs": "Czech", "da": "Danish", "de": "German", "el": "Greek",
+	"en": "English", "es": "Spanish", "fi": "Finnish", "fr": "French", "he": "Hebrew",
+	"hi": "Hindi", "hu": "Hungarian", "id": "Indonesian", "it": "Italian", "ja": "Japanese",
+	"ko": "Korean", "nl": "Dutch", "no": "Norwegian", "pl": "Polish", "pt": "Portuguese",
+	"ro": "Romanian", "ru": "Russian", "sv": "Swedish", "th": "Thai", "tr": "Turkish",
+	"uk": "Ukrainian", "vi": "Vietnamese", "zh": "Chinese",
+}
+
+func languageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+const translateRules = "Keep the meaning, tone and formatting. Leave names, product terms, code, URLs, ids and placeholders like [EMAIL_1] unchanged."
+
+// translateText translates free text into the language with the given code
+func (s *aiService) translateText(ctx context.Context, tenant, text, language string) (string, error) {
+	prompt := fmt.Sprintf("Translate the text below into %s. %s Reply with the translation only.\n\n%s",
+		languageName(language), translateRules, text)
+	resp, err := s.generate(ctx, tenant, llm.Request{Prompt: prompt, Temperature: 0.1, MaxOutputTokens: 2000})
+	if err != nil {
+		return "", err
+	}
+	translated := strings.TrimSpace(resp.Text)
+	if translated == "" {
+		return "", llm.ErrEmptyResponse
+	}
+	log.Printf("Translated %d chars into %s (%d/%d tokens)", len(text), language, resp.InputTokens, resp.OutputTokens)
+	return translated, nil
+}
+
+// translatedTicket is the title and description in the agents' language
+type translatedTicket struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// translateTicket translates title and description in one call
+func (s *aiService) translateTicket(tenant, title, description, language string) (*translatedTicket, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	req := llm.Request{
+		Prompt: fmt.Sprintf("Translate this support ticket into %s. %s\n\nTitle: %s\n\nDescription:\n%s",
+			languageName(language), translateRules, title, description),
+		Temperature:     0.1,
+		MaxOutputTokens: 2000,
+	}
+	if s.provider.SupportsStructuredOutput() {
+		req.Schema = map[string]interface{}{
+			"type": "OBJECT",
+			"properties": map[string]interface{}{
+				"title":       map[string]interface{}{"type": "STRING"},
+				"description": map[string]interface{}{"type": "STRING"},
+			},
+			"required": []string{"title", "description"},
+		}
+	} else {
+		req.Prompt += "\n\n" + `JSON only: {"title": "translated title", "description": "translated description"}`
+	}
+	resp, err := s.generate(ctx, tenant, req)
+	if err != nil {
+		return nil, err
+	}
+	text := resp.Text
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("translation is not a JSON object")
+	}
+	var t translatedTicket
+	if err := json.Unmarshal([]byte(text[start:end+1]), &t); err != nil {
+		return nil, fmt.Errorf("invalid translation JSON: %w", err)
+	}
+	if strings.TrimSpace(t.Title) == "" || strings.TrimSpace(t.Description) == "" {
+		return nil, llm.ErrEmptyResponse
+	}
+	return &t, nil
+}
+
+// translateForAgents stores the title and description in the agents'
+// language when the customer wrote in another one. title and description
+// are redacted with redaction, which restores the translation.
+func (s *aiService) translateForAgents(ticket *models.Ticket, title, description string, redaction *redact.Mapping) {
+	if ticket.Language == "" || ticket.Language == s.cfg.AgentLanguage {
+		ticket.TranslatedTitle, ticket.TranslatedDescription = "", ""
+		return
+	}
+	t, err := s.translateTicket(ticket.User.Tenant, title, description, s.cfg.AgentLanguage)
+	if err != nil {
+		// Agents still have the original; a later content update retries
+		log.Printf("Failed to translate ticket %s from %s: %v", ticket.ID, ticket.Language, err)
+		return
+	}
+	ticket.TranslatedTitle, ticket.TranslatedDescription = redaction.RestoreAll(t.Title), redaction.RestoreAll(t.Description)
+}
+
+// Translate translates an agent's reply into the customer's language, or
+// into language when given
+func (s *aiService) Translate(caller Caller, ticketID uuid.UUID, text, language string) (*models.TranslateResponse, error) {
+	ticket, err := s.authorizeTicket(caller, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if language == "" {
+		language = ticket.Language
+	}
+	if language == "" {
+		return nil, fmt.Errorf("invalid request: the ticket's language is not known yet, pass a language")
+	}
+	if language = normalizeLanguage(language); language == "" {
+		return nil, fmt.Errorf("invalid language: use an ISO 639-1 code such as \"de\"")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	redaction := s.redaction(ticket.User.Tenant)
+	translated, err := s.translateText(ctx, ticket.User.Tenant, redaction.Redact(text), language)
+	if err != nil {
+		return nil, fmt.Errorf("translation failed: %w", err)
+	}
+	logRedaction(redaction, "a reply on ticket "+ticketID.String())
+	return &models.TranslateResponse{Text: redaction.RestoreAll(translated), Language: language}, nil
+}
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// authorNames says who wrote a comment, by author role
+var authorNames = map[string]string{
+	"customer": "The customer",
+	"agent":    "An agent",
+	"ai":       "The AI assistant",
+}
+
+// notificationsFor decides who a ticket event concerns: assignment, status
+// and comment activity. Other events notify nobody.
+func (s *inboxService) notificationsFor(eventType string, data []byte) ([]models.Notification, error) {
+	switch eventType {
+	case models.EventTicketAssigned:
+		var event models.TicketAssignedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("invalid assigned event: %w", err)
+		}
+		ticket, err := s.repo.FindTicket(event.TicketID)
+		if err != nil {
+			return nil, fmt.Errorf("ticket %s not found: %w", event.TicketID, err)
+		}
+		notifications := []models.Notification{
+			notify(ticket.UserID, ticket, models.NotificationAssignment, "An agent is working on your ticket"),
+		}
+		// Agents picking a ticket up themselves know already
+		if event.AssignedBy != event.AgentID {
+			notifications = append(notifications, notify(event.AgentID, ticket, models.NotificationAssignment, "A ticket was assigned to you"))
+		}
+		return notifications, nil
+
+	case models.EventTicketUpdated:
+		var event models.TicketUpdatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("invalid updated event: %w", err)
+		}
+		// ai_queued only means classification is waiting for the provider
+		if event.NewStatus == "ai_queued" {
+			return nil, nil
+		}
+		ticket, err := s.repo.FindTicket(event.TicketID)
+		if err != nil {
+			return nil, fmt.Errorf("ticket %s not found: %w", event.TicketID, err)
+		}
+		return []models.Notification{
+			notify(ticket.UserID, ticket, models.NotificationStatus, fmt.Sprintf("Status changed from %s to %s", event.OldStatus, event.NewStatus)),
+		}, nil
+
+	case models.EventTicketContentUpdated:
+		var event models.TicketContentUpdatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("invalid content updated event: %w", err)
+		}
+		if event.CommentID == nil {
+			return nil, nil
+		}
+		ticket, err := s.repo.FindTicket(event.TicketID)
+		if err != nil {
+			return nil, fmt.Errorf("ticket %s not found: %w", event.TicketID, err)
+		}
+		title := "New comment"
+		if name, ok := authorNames[event.AuthorRole]; ok {
+			title = name + " commented"
+		}
+		if event.Internal {
+			title = "New internal note"
+		}
+		// The customer and the assigned agent, except whoever wrote it;
+		// internal notes are for agents only
+		var recipients []uuid.UUID
+		if !event.Internal {
+			recipients = append(recipients, ticket.UserID)
+		}
+		if ticket.AgentID != nil {
+			recipients = append(recipients, *ticket.AgentID)
+		}
+		var notifications []models.Notification
+		for _, userID := range recipients {
+			if event.AuthorID != nil && *event.AuthorID == userID {
+				continue
+			}
+			notifications = append(notifications, notify(userID, ticket, models.NotificationComment, title))
+		}
+		return notifications, nil
+	}
+	return nil, nil
+}
+
+func notify(userID uuid.UUID, ticket *models.Ticket, kind, title string) models.Notification {
+	return models.Notification{UserID: userID, TicketID: ticket.ID, Kind: kind, Title: title, Body: ticket.Title}
+}
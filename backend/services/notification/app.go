@@ -1,13 +1,58 @@
 package notification
 
 import (
-	"github.com/joho/godotenv"
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/db"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"ai-ticketing-backend/services/notification/repository"
+	"context"
+	"encoding/json"
+	"log"
 )
 
-func Setup() NotificationService {
-	_ = godotenv.Load()
+// Services groups what the notification consumer and HTTP server use
+type Services struct {
+	Dispatcher *Dispatcher
+	Inbox      InboxService
+}
 
-	svc := NewNotificationService()
+func Setup(cfg *config.Config) *Services {
+	dbConn, err := db.New(cfg.DB.DSN())
+	if err != nil {
+		panic(err)
+	}
+	cache := redis.New(cfg.Redis.Addr)
 
-	return svc
-}
\ No newline at end of file
+	repo := repository.NewNotificationRepository(dbConn)
+	inbox := NewInboxService(repo, cache, cfg.Notification)
+	// The ticket service's realtime stream pushes these to the user
+	inbox.OnDeliver(func(n *models.Notification) {
+		data, err := json.Marshal(n)
+		if err != nil {
+			log.Printf("Failed to marshal notification %s: %v", n.ID, err)
+			return
+		}
+		if err := cache.Publish(context.Background(), models.NotificationChannel, data).Err(); err != nil {
+			log.Printf("Failed to publish notification %s: %v", n.ID, err)
+		}
+	})
+
+	// The in-app inbox and webhook take every event, the rest status changes only
+	n := cfg.Notification
+	dispatcher := NewDispatcher(repo, n)
+	dispatcher.Register(NewInAppChannel(inbox))
+	if n.EmailSender != "" && n.EmailPassword != "" && n.EmailReceiver != "" {
+		dispatcher.Register(NewEmailChannel(n), models.EventTicketUpdated)
+	}
+	if n.SlackWebhookURL != "" {
+		dispatcher.Register(NewSlackChannel(n.SlackWebhookURL), models.EventTicketUpdated)
+	}
+	if n.TeamsWebhookURL != "" {
+		dispatcher.Register(NewTeamsChannel(n.TeamsWebhookURL), models.EventTicketUpdated)
+	}
+	if n.WebhookURL != "" {
+		dispatcher.Register(NewWebhookChannel(n.WebhookURL, n.WebhookSecret))
+	}
+	return &Services{Dispatcher: dispatcher, Inbox: inbox}
+}
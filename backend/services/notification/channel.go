@@ -0,0 +1,79 @@
+package notification
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Channel delivers notifications one way: email, chat, webhook, inbox.
+// Send should make one attempt; the dispatcher retries and records it.
+type Channel interface {
+	Name() string // Identifies the channel in delivery records and logs
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Message is one ticket event to deliver, with a rendering for people
+type Message struct {
+	EventID   string
+	EventType string
+	TicketID  uuid.UUID
+	Subject   string
+	Text      string
+	Data      []byte // The event as published
+}
+
+// NewMessage renders a ticket event
+func NewMessage(eventID, eventType string, data []byte) (*Message, error) {
+	var event struct {
+		TicketID  uuid.UUID `json:"ticket_id"`
+		UserID    uuid.UUID `json:"user_id"`
+		OldStatus string    `json:"old_status"`
+		NewStatus string    `json:"new_status"`
+		AgentID   uuid.UUID `json:"agent_id"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	msg := &Message{EventID: eventID, EventType: eventType, TicketID: event.TicketID, Data: data}
+	switch eventType {
+	case models.EventTicketUpdated:
+		msg.Subject = "Ticket Update: " + event.TicketID.String()
+		msg.Text = fmt.Sprintf("Ticket %s updated: Status changed from %s to %s. User: %s", event.TicketID, event.OldStatus, event.NewStatus, event.UserID)
+	case models.EventTicketAssigned:
+		msg.Subject = "Ticket Assigned: " + event.TicketID.String()
+		msg.Text = fmt.Sprintf("Ticket %s was assigned to agent %s. User: %s", event.TicketID, event.AgentID, event.UserID)
+	default:
+		msg.Subject = "Ticket Activity: " + event.TicketID.String()
+		msg.Text = fmt.Sprintf("Ticket %s: %s. User: %s", event.TicketID, eventType, event.UserID)
+	}
+	return msg, nil
+}
+
+// postJSON posts body to url and fails unless the answer is 2xx
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		answer, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, answer)
+	}
+	return nil
+}
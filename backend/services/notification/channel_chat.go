@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// slackChannel posts to a Slack incoming webhook
+type slackChannel struct {
+	url    string
+	client *http.Client
+}
+
+func NewSlackChannel(url string) Channel {
+	return &slackChannel{url: url, client: &http.Client{}}
+}
+
+func (c *slackChannel) Name() string { return "slack" }
+
+func (c *slackChannel) Send(ctx context.Context, msg *Message) error {
+	payload, err := json.Marshal(map[string]string{"text": msg.Text})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, c.client, c.url, payload, nil)
+}
+
+// teamsChannel posts an Adaptive Card to a Microsoft Teams workflow webhook
+type teamsChannel struct {
+	url    string
+	client *http.Client
+}
+
+func NewTeamsChannel(url string) Channel {
+	return &teamsChannel{url: url, client: &http.Client{}}
+}
+
+func (c *teamsChannel) Name() string { return "teams" }
+
+func (c *teamsChannel) Send(ctx context.Context, msg *Message) error {
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body": []map[string]interface{}{
+			{"type": "TextBlock", "text": msg.Subject, "weight": "Bolder", "wrap": true},
+			{"type": "TextBlock", "text": msg.Text, "wrap": true},
+		},
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": "application/vnd.microsoft.card.adaptive", "content": card},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, c.client, c.url, payload, nil)
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"ai-ticketing-backend/internal/pkg/config"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+)
+
+// emailChannel sends through SMTP to the configured receiver
+type emailChannel struct {
+	cfg config.NotificationConfig
+}
+
+func NewEmailChannel(cfg config.NotificationConfig) Channel {
+	return &emailChannel{cfg: cfg}
+}
+
+func (c *emailChannel) Name() string { return "email" }
+
+// Send does what smtp.SendMail does on a connection bound to ctx, so a slow
+// server fails the delivery at the deadline instead of holding the worker
+func (c *emailChannel) Send(ctx context.Context, msg *Message) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(c.cfg.SMTPHost, c.cfg.SMTPPort))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// Closing the connection unblocks a send cancelled before its deadline
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := c.send(conn, msg); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *emailChannel) send(conn net.Conn, msg *Message) error {
+	client, err := smtp.NewClient(conn, c.cfg.SMTPHost)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.cfg.SMTPHost}); err != nil {
+			return err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok {
+		auth := smtp.PlainAuth("", c.cfg.EmailSender, c.cfg.EmailPassword, c.cfg.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.cfg.EmailSender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(c.cfg.EmailReceiver); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	body := "To: " + c.cfg.EmailReceiver + "\r\n" +
+		"Subject: " + msg.Subject + "\r\n" +
+		"\r\n" + msg.Text + "\r\n"
+	if _, err := w.Write([]byte(body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
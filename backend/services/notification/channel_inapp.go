@@ -0,0 +1,19 @@
+package notification
+
+import "context"
+
+// inAppChannel records the event in the inbox of each user it concerns
+type inAppChannel struct {
+	inbox InboxService
+}
+
+func NewInAppChannel(inbox InboxService) Channel {
+	return &inAppChannel{inbox: inbox}
+}
+
+func (c *inAppChannel) Name() string { return "in_app" }
+
+// Send is safe to retry: an event notifies each user at most once
+func (c *inAppChannel) Send(ctx context.Context, msg *Message) error {
+	return c.inbox.Record(msg.EventID, msg.EventType, msg.Data)
+}
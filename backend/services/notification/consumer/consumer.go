@@ -2,63 +2,66 @@ package consumer
 
 import (
 	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
 	service "ai-ticketing-backend/services/notification"
 	"context"
-	"encoding/json"
 	"log"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 
 	"github.com/segmentio/kafka-go"
 )
 
-func StartConsumer(svc service.NotificationService) {
-	kafkaBroker := os.Getenv("KAFKA_BROKER")
-	if kafkaBroker == "" {
-		kafkaBroker = "kafka:9092"
-	}
+// StartConsumer hands ticket events to the dispatcher. Offsets are
+// committed once an event's deliveries are recorded, so a crash before
+// that redelivers it; deliveries themselves are retried from their records.
+func StartConsumer(svc *service.Services, cfg config.KafkaConfig) {
 	r := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  strings.Split(kafkaBroker, ","),
-		Topic:    "ticket-events",
+		Brokers:  cfg.Brokers,
+		Topic:    cfg.Topic,
 		GroupID:  "notification-consumer-group",
 		MinBytes: 10e3,
 		MaxBytes: 10e6,
 	})
 	defer r.Close()
 
-	log.Println("Notification Consumer listening on ticket-events...")
-	sigchan := make(chan os.Signal, 1)
-	signal.Notify(sigchan, syscall.SIGINT, syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	log.Println("Notification Consumer listening on", cfg.Topic)
 	for {
-		select {
-		case sig := <-sigchan:
-			log.Println("Caught signal", sig)
-			return
-		default:
-			msg, err := r.ReadMessage(context.Background())
-			if err != nil {
-				log.Printf("Error reading message: %v", err)
-				continue
-			}
-			// Filter for updated events
-			var event models.TicketUpdatedEvent
-			if err := json.Unmarshal(msg.Value, &event); err != nil {
-				var createdEvent models.TicketCreatedEvent
-				if json.Unmarshal(msg.Value, &createdEvent) == nil {
-					log.Printf("Skipped created event %s (no notification)", createdEvent.TicketID)
-				} else {
-					log.Printf("Failed to unmarshal event: %v", err)
-				}
-				continue
-			}
-			if err := svc.SendUpdatedNotification(&event); err != nil {
-				log.Printf("Failed to send notification for %s: %v", event.TicketID, err)
-			} else {
-				log.Printf("Notification sent for updated ticket %s", event.TicketID)
+		msg, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Println("Shutting down notification consumer")
+				return
 			}
+			log.Printf("Error reading message: %v", err)
+			continue
 		}
+		handleMessage(svc, msg)
+		if err := r.CommitMessages(context.Background(), msg); err != nil {
+			log.Printf("Failed to commit offset %d of partition %d: %v", msg.Offset, msg.Partition, err)
+		}
+	}
+}
+
+func handleMessage(svc *service.Services, msg kafka.Message) {
+	eventType := models.EventType(msg.Value)
+	if eventType == "" {
+		log.Printf("Skipped unknown event: %s", string(msg.Value))
+		return
+	}
+	message, err := service.NewMessage(eventID(msg), eventType, msg.Value)
+	if err != nil {
+		log.Printf("Failed to read %s event: %v", eventType, err)
+		return
 	}
+	svc.Dispatcher.Dispatch(message)
+}
+
+// eventID identifies a message for deduplication
+func eventID(msg kafka.Message) string {
+	return models.EventID(msg.Value, msg.Topic, msg.Partition, msg.Offset)
 }
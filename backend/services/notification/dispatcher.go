@@ -0,0 +1,276 @@
+package notification
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/services/notification/repository"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// maxBackoff caps the wait between delivery attempts
+const maxBackoff = time.Minute
+
+// pendingLease is how long a queued delivery may wait before the retry
+// sweep assumes its process went away and queues it again
+const pendingLease = 15 * time.Minute
+
+// Dispatcher delivers each event through every registered channel that
+// takes it. Channels are independent: each has its own queue, worker,
+// retries and delivery record, so one failing or slow channel doesn't hold
+// back or skip the others.
+type Dispatcher struct {
+	repo        repository.NotificationRepository // Delivery records; may be nil
+	workers     []*worker
+	retries     int
+	backoff     time.Duration
+	timeout     time.Duration
+	queueSize   int
+	maxAttempts int
+
+	mu       sync.Mutex
+	inflight map[string]bool // Queued or sending, by event and channel
+}
+
+type worker struct {
+	channel Channel
+	events  map[string]bool // nil takes every event
+	queue   chan *job
+}
+
+type job struct {
+	msg      *Message
+	delivery *models.NotificationDelivery
+}
+
+func NewDispatcher(repo repository.NotificationRepository, cfg config.NotificationConfig) *Dispatcher {
+	if cfg.DeliveryQueue < 1 {
+		cfg.DeliveryQueue = 1
+	}
+	return &Dispatcher{
+		repo:        repo,
+		retries:     cfg.DeliveryRetries,
+		backoff:     cfg.DeliveryBackoff,
+		timeout:     cfg.DeliveryTimeout,
+		queueSize:   cfg.DeliveryQueue,
+		maxAttempts: cfg.DeliveryMaxAttempts,
+		inflight:    map[string]bool{},
+	}
+}
+
+// Register adds a channel for the given event types, or for every event
+// when none are given, and starts its worker
+func (d *Dispatcher) Register(ch Channel, eventTypes ...string) {
+	w := &worker{channel: ch, queue: make(chan *job, d.queueSize)}
+	if len(eventTypes) > 0 {
+		w.events = map[string]bool{}
+		for _, t := range eventTypes {
+			w.events[t] = true
+		}
+	}
+	d.workers = append(d.workers, w)
+	go d.run(w)
+	log.Printf("Registered %s notification channel", ch.Name())
+}
+
+// Dispatch records a pending delivery of msg for each of its channels and
+// queues it there. It returns without waiting for the deliveries, so the
+// event can be committed: from here on the records carry it.
+func (d *Dispatcher) Dispatch(msg *Message) {
+	for _, w := range d.workers {
+		if w.events != nil && !w.events[msg.EventType] {
+			continue
+		}
+		key := deliveryKey(msg.EventID, w.channel.Name())
+		if !d.reserve(key) {
+			continue // A redelivery of an event that is still queued
+		}
+		delivery := d.findDelivery(w.channel, msg)
+		if delivery.Status == models.DeliverySent {
+			log.Printf("Event %s already delivered via %s, skipping", msg.EventID, w.channel.Name())
+			d.release(key)
+			continue
+		}
+		delivery.Status = models.DeliveryPending
+		delivery.Payload = string(msg.Data)
+		d.save(w.channel, msg, delivery)
+		d.enqueue(w, key, &job{msg: msg, delivery: delivery})
+	}
+}
+
+// Retry queues failed deliveries that have attempts left, and pending ones
+// a stopped process left behind; it returns how many were queued
+func (d *Dispatcher) Retry(limit int) (int, error) {
+	if d.repo == nil || len(d.workers) == 0 {
+		return 0, nil
+	}
+	workers := map[string]*worker{}
+	var channels []string
+	for _, w := range d.workers {
+		workers[w.channel.Name()] = w
+		channels = append(channels, w.channel.Name())
+	}
+	deliveries, err := d.repo.ClaimDeliveries(channels, d.maxAttempts, time.Now().Add(-pendingLease), limit)
+	if err != nil {
+		return 0, err
+	}
+	queued := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		w := workers[delivery.Channel]
+		msg, err := NewMessage(delivery.EventID, delivery.EventType, []byte(delivery.Payload))
+		if err != nil {
+			// Nothing to send; keep it out of later sweeps
+			delivery.Status, delivery.Attempts, delivery.LastError = models.DeliveryFailed, d.maxAttempts, err.Error()
+			d.save(w.channel, &Message{EventID: delivery.EventID}, delivery)
+			continue
+		}
+		key := deliveryKey(delivery.EventID, delivery.Channel)
+		if !d.reserve(key) {
+			continue
+		}
+		d.enqueue(w, key, &job{msg: msg, delivery: delivery})
+		queued++
+	}
+	return queued, nil
+}
+
+// retryBatch is how many deliveries one sweep claims. They are queued at
+// once, and whatever doesn't fit in a channel's queue waits for the next
+// sweep, so a larger batch wouldn't drain an outage backlog any faster.
+const retryBatch = 200
+
+// RetryEvery sweeps for deliveries to retry every interval, at least once
+// an hour
+func (d *Dispatcher) RetryEvery(interval time.Duration) {
+	if interval <= 0 || interval > time.Hour {
+		interval = time.Hour
+	}
+	log.Printf("Retrying failed notification deliveries every %s", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		queued, err := d.Retry(retryBatch)
+		if err != nil {
+			log.Printf("Delivery retry failed: %v", err)
+			continue
+		}
+		if queued > 0 {
+			log.Printf("Queued %d notification deliveries for retry", queued)
+		}
+	}
+}
+
+// enqueue hands the job to the channel's worker. A full queue leaves the
+// delivery failed for the retry sweep rather than blocking the consumer.
+func (d *Dispatcher) enqueue(w *worker, key string, j *job) {
+	select {
+	case w.queue <- j:
+	default:
+		d.release(key)
+		log.Printf("%s queue is full, event %s waits for the retry sweep", w.channel.Name(), j.msg.EventID)
+		j.delivery.Status = models.DeliveryFailed
+		j.delivery.LastError = "queue full"
+		d.save(w.channel, j.msg, j.delivery)
+	}
+}
+
+func (d *Dispatcher) run(w *worker) {
+	for j := range w.queue {
+		d.deliver(w.channel, j.msg, j.delivery)
+		d.release(deliveryKey(j.msg.EventID, w.channel.Name()))
+	}
+}
+
+// deliver sends msg through ch with retries and records the outcome
+func (d *Dispatcher) deliver(ch Channel, msg *Message, delivery *models.NotificationDelivery) {
+	var err error
+	for attempt := 0; attempt <= d.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(d.retryDelay(attempt))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+		err = ch.Send(ctx, msg)
+		cancel()
+		delivery.Attempts++
+		if err == nil {
+			break
+		}
+		log.Printf("%s delivery of event %s failed (attempt %d/%d): %v", ch.Name(), msg.EventID, attempt+1, d.retries+1, err)
+	}
+
+	if err != nil {
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = err.Error()
+	} else {
+		now := time.Now()
+		delivery.Status = models.DeliverySent
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		log.Printf("Delivered %s event %s via %s", msg.EventType, msg.EventID, ch.Name())
+	}
+	d.save(ch, msg, delivery)
+}
+
+// findDelivery loads the channel's record of the event, or starts one
+func (d *Dispatcher) findDelivery(ch Channel, msg *Message) *models.NotificationDelivery {
+	if d.repo != nil {
+		delivery, err := d.repo.FindDelivery(msg.EventID, ch.Name())
+		if err != nil {
+			log.Printf("Failed to load %s delivery of event %s: %v", ch.Name(), msg.EventID, err)
+		} else if delivery != nil {
+			return delivery
+		}
+	}
+	return &models.NotificationDelivery{
+		EventID:   msg.EventID,
+		Channel:   ch.Name(),
+		EventType: msg.EventType,
+		TicketID:  msg.TicketID,
+		Status:    models.DeliveryPending,
+	}
+}
+
+func (d *Dispatcher) save(ch Channel, msg *Message, delivery *models.NotificationDelivery) {
+	if d.repo == nil {
+		return
+	}
+	if err := d.repo.SaveDelivery(delivery); err != nil {
+		log.Printf("Failed to record %s delivery of event %s: %v", ch.Name(), msg.EventID, err)
+	}
+}
+
+// reserve marks the delivery in flight, reporting false if it already is
+func (d *Dispatcher) reserve(key string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.inflight[key] {
+		return false
+	}
+	d.inflight[key] = true
+	return true
+}
+
+func (d *Dispatcher) release(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inflight, key)
+}
+
+func deliveryKey(eventID, channel string) string {
+	return eventID + "/" + channel
+}
+
+func (d *Dispatcher) retryDelay(attempt int) time.Duration {
+	if d.backoff <= 0 {
+		return 0
+	}
+	wait := d.backoff << (attempt - 1)
+	if wait <= 0 || wait > maxBackoff {
+		wait = maxBackoff
+	}
+	return wait
+}
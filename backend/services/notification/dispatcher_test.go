@@ -0,0 +1,264 @@
+package notification
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/services/notification/repository"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDeliveries keeps delivery records in memory, by event and channel
+type fakeDeliveries struct {
+	repository.NotificationRepository
+	mu      sync.Mutex
+	records map[string]models.NotificationDelivery
+	claim   []models.NotificationDelivery
+}
+
+func newFakeDeliveries() *fakeDeliveries {
+	return &fakeDeliveries{records: map[string]models.NotificationDelivery{}}
+}
+
+func (r *fakeDeliveries) FindDelivery(eventID, channel string) (*models.NotificationDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d, ok := r.records[deliveryKey(eventID, channel)]; ok {
+		return &d, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeDeliveries) SaveDelivery(d *models.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[deliveryKey(d.EventID, d.Channel)] = *d
+	return nil
+}
+
+func (r *fakeDeliveries) ClaimDeliveries(channels []string, maxAttempts int, staleBefore time.Time, limit int) ([]models.NotificationDelivery, error) {
+	return r.claim, nil
+}
+
+func (r *fakeDeliveries) record(eventID, channel string) (models.NotificationDelivery, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.records[deliveryKey(eventID, channel)]
+	return d, ok
+}
+
+// fakeChannel fails its first failures sends, then succeeds; hold, when
+// set, blocks every send until it is closed
+type fakeChannel struct {
+	name     string
+	failures int
+	hold     chan struct{}
+	mu       sync.Mutex
+	sends    int
+}
+
+func (c *fakeChannel) Name() string { return c.name }
+
+func (c *fakeChannel) Send(ctx context.Context, msg *Message) error {
+	c.mu.Lock()
+	c.sends++
+	n := c.sends
+	c.mu.Unlock()
+	if c.hold != nil {
+		<-c.hold
+	}
+	if n <= c.failures {
+		return errors.New("unreachable")
+	}
+	return nil
+}
+
+func (c *fakeChannel) sent() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sends
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func testDispatcher(repo repository.NotificationRepository, queue int) *Dispatcher {
+	return NewDispatcher(repo, config.NotificationConfig{
+		DeliveryRetries:     1,
+		DeliveryBackoff:     time.Millisecond,
+		DeliveryTimeout:     time.Second,
+		DeliveryQueue:       queue,
+		DeliveryMaxAttempts: 5,
+	})
+}
+
+func testMessage(t *testing.T, eventID, eventType string) *Message {
+	t.Helper()
+	msg, err := NewMessage(eventID, eventType, []byte(`{"ticket_id": "6b0e6f4e-3a5c-4d0e-9a55-1f3c3a7d9b11"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int    // Of the channel under test
+		events       string // Event type the channel takes; empty for all
+		previous     string // Status already recorded; empty for none
+		wantStatus   string // Empty when nothing is recorded
+		wantAttempts int
+		wantSends    int
+	}{
+		{"sent", 0, "", "", models.DeliverySent, 1, 1},
+		{"sent on retry", 1, "", "", models.DeliverySent, 2, 2},
+		{"retries exhausted", 2, "", "", models.DeliveryFailed, 2, 2},
+		{"event not taken", 0, models.EventTicketAssigned, "", "", 0, 0},
+		{"failed before, sent now", 0, "", models.DeliveryFailed, models.DeliverySent, 4, 1},
+		{"already sent", 0, "", models.DeliverySent, models.DeliverySent, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDeliveries()
+			if tt.previous != "" {
+				repo.SaveDelivery(&models.NotificationDelivery{EventID: "e1", Channel: "test", Status: tt.previous, Attempts: 3})
+			}
+			ch := &fakeChannel{name: "test", failures: tt.failures}
+			// Always fails, to show it doesn't hold back the channel under test
+			broken := &fakeChannel{name: "broken", failures: 100}
+			d := testDispatcher(repo, 10)
+			d.Register(broken)
+			if tt.events != "" {
+				d.Register(ch, tt.events)
+			} else {
+				d.Register(ch)
+			}
+
+			d.Dispatch(testMessage(t, "e1", models.EventTicketUpdated))
+			waitFor(t, "the broken channel to give up", func() bool {
+				rec, _ := repo.record("e1", "broken")
+				return rec.Status == models.DeliveryFailed
+			})
+			if tt.wantStatus != "" {
+				waitFor(t, "the delivery to finish", func() bool {
+					rec, _ := repo.record("e1", "test")
+					return rec.Status == tt.wantStatus
+				})
+			}
+
+			rec, ok := repo.record("e1", "test")
+			switch {
+			case tt.wantStatus == "" && ok:
+				t.Errorf("recorded %+v, want no delivery", rec)
+			case tt.wantStatus != "" && rec.Attempts != tt.wantAttempts:
+				t.Errorf("recorded %d attempts, want %d", rec.Attempts, tt.wantAttempts)
+			}
+			if rec.Status == models.DeliverySent && rec.DeliveredAt == nil && tt.previous != models.DeliverySent {
+				t.Error("sent delivery has no DeliveredAt")
+			}
+			if got := ch.sent(); got != tt.wantSends {
+				t.Errorf("%d sends, want %d", got, tt.wantSends)
+			}
+		})
+	}
+}
+
+func TestDispatchFullQueue(t *testing.T) {
+	repo := newFakeDeliveries()
+	ch := &fakeChannel{name: "slow", hold: make(chan struct{})}
+	d := testDispatcher(repo, 1)
+	d.Register(ch)
+
+	d.Dispatch(testMessage(t, "e1", models.EventTicketUpdated))
+	waitFor(t, "the worker to pick up e1", func() bool { return ch.sent() == 1 })
+	d.Dispatch(testMessage(t, "e2", models.EventTicketUpdated)) // Fills the queue
+	d.Dispatch(testMessage(t, "e3", models.EventTicketUpdated))
+	d.Dispatch(testMessage(t, "e1", models.EventTicketUpdated)) // Still in flight
+
+	if rec, _ := repo.record("e3", "slow"); rec.Status != models.DeliveryFailed || rec.LastError != "queue full" {
+		t.Errorf("e3 = %+v, want it failed with a full queue", rec)
+	}
+	close(ch.hold)
+	waitFor(t, "e1 and e2 to be sent", func() bool {
+		e1, _ := repo.record("e1", "slow")
+		e2, _ := repo.record("e2", "slow")
+		return e1.Status == models.DeliverySent && e2.Status == models.DeliverySent
+	})
+	if got := ch.sent(); got != 2 {
+		t.Errorf("%d sends, want 2", got)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	payload := `{"ticket_id": "6b0e6f4e-3a5c-4d0e-9a55-1f3c3a7d9b11"}`
+	tests := []struct {
+		name         string
+		claim        []models.NotificationDelivery
+		noRepo       bool
+		wantQueued   int
+		wantStatus   map[string]string // By event ID
+		wantAttempts map[string]int
+	}{
+		{
+			name: "failed and stale deliveries resent",
+			claim: []models.NotificationDelivery{
+				{EventID: "e1", Channel: "test", EventType: models.EventTicketUpdated, Payload: payload, Status: models.DeliveryPending, Attempts: 2},
+				{EventID: "e2", Channel: "test", EventType: models.EventTicketAssigned, Payload: payload, Status: models.DeliveryPending},
+			},
+			wantQueued:   2,
+			wantStatus:   map[string]string{"e1": models.DeliverySent, "e2": models.DeliverySent},
+			wantAttempts: map[string]int{"e1": 3, "e2": 1},
+		},
+		{
+			name:         "unreadable payload given up",
+			claim:        []models.NotificationDelivery{{EventID: "e1", Channel: "test", EventType: models.EventTicketUpdated, Payload: "{", Attempts: 1}},
+			wantStatus:   map[string]string{"e1": models.DeliveryFailed},
+			wantAttempts: map[string]int{"e1": 5},
+		},
+		{name: "no delivery records", noRepo: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDeliveries()
+			repo.claim = tt.claim
+			ch := &fakeChannel{name: "test"}
+			d := testDispatcher(repo, 10)
+			if tt.noRepo {
+				d = testDispatcher(nil, 10)
+			}
+			d.Register(ch)
+
+			queued, err := d.Retry(retryBatch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if queued != tt.wantQueued {
+				t.Errorf("Retry queued %d, want %d", queued, tt.wantQueued)
+			}
+			for eventID, status := range tt.wantStatus {
+				waitFor(t, eventID+" to be "+status, func() bool {
+					rec, _ := repo.record(eventID, "test")
+					return rec.Status == status
+				})
+				if rec, _ := repo.record(eventID, "test"); rec.Attempts != tt.wantAttempts[eventID] {
+					t.Errorf("%s has %d attempts, want %d", eventID, rec.Attempts, tt.wantAttempts[eventID])
+				}
+			}
+			if got := ch.sent(); got != tt.wantQueued {
+				t.Errorf("%d sends, want %d", got, tt.wantQueued)
+			}
+		})
+	}
+}
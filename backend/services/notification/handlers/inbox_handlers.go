@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"ai-ticketing-backend/services/notification"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InboxHandlers struct {
+	svc notification.InboxService
+}
+
+func NewInboxHandlers(svc notification.InboxService) *InboxHandlers {
+	return &InboxHandlers{svc: svc}
+}
+
+// List for GET /api/v1/notifications?unread=true&before=<RFC3339>; the
+// next page starts before the last notification's created_at
+func (h *InboxHandlers) List(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	var before time.Time
+	if s := c.Query("before"); s != "" {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid before: use RFC 3339"})
+			return
+		}
+		before = t
+	}
+	list, err := h.svc.List(userID, c.Query("unread") == "true", before)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// UnreadCount for GET /api/v1/notifications/unread-count
+func (h *InboxHandlers) UnreadCount(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	count, err := h.svc.UnreadCount(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// MarkRead for POST /api/v1/notifications/:id/read
+func (h *InboxHandlers) MarkRead(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	n, err := h.svc.MarkRead(userID, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "notification not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, n)
+}
+
+// MarkAllRead for POST /api/v1/notifications/read-all
+func (h *InboxHandlers) MarkAllRead(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	marked, err := h.svc.MarkAllRead(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
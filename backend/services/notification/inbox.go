@@ -0,0 +1,150 @@
+package notification
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"ai-ticketing-backend/services/notification/repository"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InboxService keeps each user's in-app notifications
+type InboxService interface {
+	Record(eventID, eventType string, data []byte) error // Notifies the users a ticket event concerns
+	List(userID uuid.UUID, unreadOnly bool, before time.Time) (*models.NotificationList, error)
+	UnreadCount(userID uuid.UUID) (int64, error)
+	MarkRead(userID, id uuid.UUID) (*models.Notification, error)
+	MarkAllRead(userID uuid.UUID) (int64, error)
+	OnDeliver(hook DeliveryHook)
+}
+
+// DeliveryHook is called with every new notification, e.g. to push it to
+// the user's open sessions
+type DeliveryHook func(n *models.Notification)
+
+type inboxService struct {
+	repo  repository.NotificationRepository
+	cache *redis.Client // Unread counts; nil when off
+	cfg   config.NotificationConfig
+	hooks []DeliveryHook
+}
+
+// NewInboxService caches unread counts unless UnreadCacheTTL is 0: every
+// key the cache writes has to expire, or each change would leave one behind
+func NewInboxService(repo repository.NotificationRepository, cache *redis.Client, cfg config.NotificationConfig) InboxService {
+	if cfg.UnreadCacheTTL <= 0 {
+		cache = nil
+	}
+	return &inboxService{repo: repo, cache: cache, cfg: cfg}
+}
+
+func (s *inboxService) OnDeliver(hook DeliveryHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+func (s *inboxService) Record(eventID, eventType string, data []byte) error {
+	notifications, err := s.notificationsFor(eventType, data)
+	if err != nil {
+		return err
+	}
+	for i := range notifications {
+		n := &notifications[i]
+		n.EventID = eventID
+		created, err := s.repo.Create(n)
+		if err != nil {
+			return fmt.Errorf("failed to save notification: %w", err)
+		}
+		if !created {
+			log.Printf("Event %s already notified user %s, skipping", eventID, n.UserID)
+			continue
+		}
+		s.clearUnread(n.UserID)
+		for _, hook := range s.hooks {
+			hook(n)
+		}
+		log.Printf("Notified user %s of %s on ticket %s", n.UserID, n.Kind, n.TicketID)
+	}
+	return nil
+}
+
+func (s *inboxService) List(userID uuid.UUID, unreadOnly bool, before time.Time) (*models.NotificationList, error) {
+	notifications, err := s.repo.List(userID, unreadOnly, before, s.cfg.InboxPageSize)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.UnreadCount(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationList{Notifications: notifications, Unread: unread}, nil
+}
+
+// UnreadCount is served from Redis when cached. Counts are cached under the
+// user's unread version, which every change bumps, so a count read from the
+// DB before a change can't be cached as current after it.
+func (s *inboxService) UnreadCount(userID uuid.UUID) (int64, error) {
+	if s.cache == nil {
+		return s.repo.CountUnread(userID)
+	}
+	ctx := context.Background()
+	version, err := s.cache.CounterGet(ctx, unreadVersionKey(userID))
+	if err != nil {
+		log.Printf("Failed to read unread version of user %s: %v", userID, err)
+		return s.repo.CountUnread(userID)
+	}
+	key := unreadKey(userID, version)
+	var count int64
+	if s.cache.CacheGet(ctx, key, &count) == nil {
+		return count, nil
+	}
+	count, err = s.repo.CountUnread(userID)
+	if err != nil {
+		return 0, err
+	}
+	s.cache.CacheSet(ctx, key, count, s.cfg.UnreadCacheTTL)
+	// Outlives every count cached under it, so versions are never reused
+	s.cache.Expire(ctx, unreadVersionKey(userID), 2*s.cfg.UnreadCacheTTL)
+	return count, nil
+}
+
+func (s *inboxService) MarkRead(userID, id uuid.UUID) (*models.Notification, error) {
+	n, err := s.repo.MarkRead(id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("notification not found: %w", err)
+	}
+	s.clearUnread(userID)
+	return n, nil
+}
+
+func (s *inboxService) MarkAllRead(userID uuid.UUID) (int64, error) {
+	marked, err := s.repo.MarkAllRead(userID)
+	if err != nil {
+		return 0, err
+	}
+	s.clearUnread(userID)
+	return marked, nil
+}
+
+// clearUnread moves the user to a new unread version, leaving cached counts
+// of earlier versions to expire
+func (s *inboxService) clearUnread(userID uuid.UUID) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.CounterIncr(context.Background(), unreadVersionKey(userID), 2*s.cfg.UnreadCacheTTL); err != nil {
+		log.Printf("Failed to clear unread count of user %s: %v", userID, err)
+	}
+}
+
+func unreadKey(userID uuid.UUID, version int64) string {
+	return fmt.Sprintf("notifications:unread:%s:%d", userID, version)
+}
+
+func unreadVersionKey(userID uuid.UUID) string {
+	return "notifications:unread-version:" + userID.String()
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/db"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	Create(n *models.Notification) (bool, error) // false when the event already notified the user
+	List(userID uuid.UUID, unreadOnly bool, before time.Time, limit int) ([]models.Notification, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	MarkRead(id, userID uuid.UUID) (*models.Notification, error)
+	MarkAllRead(userID uuid.UUID) (int64, error)
+	FindTicket(id uuid.UUID) (*models.Ticket, error)
+	FindDelivery(eventID, channel string) (*models.NotificationDelivery, error) // nil when the event wasn't sent through channel yet
+	SaveDelivery(d *models.NotificationDelivery) error
+	// ClaimDeliveries marks up to limit deliveries through channels pending
+	// again and returns them: failed ones with attempts left, and pending
+	// ones last touched before staleBefore, whose process went away
+	ClaimDeliveries(channels []string, maxAttempts int, staleBefore time.Time, limit int) ([]models.NotificationDelivery, error)
+}
+
+type notificationRepository struct {
+	db *db.DB
+}
+
+func NewNotificationRepository(db *db.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(n *models.Notification) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(n)
+	return result.RowsAffected > 0, result.Error
+}
+
+// List returns the user's notifications created before the given time, newest first
+func (r *notificationRepository) List(userID uuid.UUID, unreadOnly bool, before time.Time, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	q := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+	if !before.IsZero() {
+		q = q.Where("created_at < ?", before)
+	}
+	err := q.Order("created_at DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of the user's notifications read; already read ones keep their time
+func (r *notificationRepository) MarkRead(id, userID uuid.UUID) (*models.Notification, error) {
+	var n models.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&n).Error; err != nil {
+		return nil, err
+	}
+	if n.ReadAt != nil {
+		return &n, nil
+	}
+	now := time.Now()
+	if err := r.db.Model(&n).Update("read_at", now).Error; err != nil {
+		return nil, err
+	}
+	n.ReadAt = &now
+	return &n, nil
+}
+
+func (r *notificationRepository) MarkAllRead(userID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) FindTicket(id uuid.UUID) (*models.Ticket, error) {
+	var ticket models.Ticket
+	if err := r.db.First(&ticket, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (r *notificationRepository) FindDelivery(eventID, channel string) (*models.NotificationDelivery, error) {
+	var d models.NotificationDelivery
+	err := r.db.Where("event_id = ? AND channel = ?", eventID, channel).First(&d).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *notificationRepository) SaveDelivery(d *models.NotificationDelivery) error {
+	return r.db.Save(d).Error
+}
+
+func (r *notificationRepository) ClaimDeliveries(channels []string, maxAttempts int, staleBefore time.Time, limit int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	// SKIP LOCKED lets every instance sweep without claiming the same rows
+	err := r.db.Raw(`UPDATE notification_deliveries SET status = ?, updated_at = NOW() WHERE id IN (
+		SELECT id FROM notification_deliveries
+		WHERE channel IN ? AND attempts < ? AND (status = ? OR (status = ? AND updated_at < ?))
+		ORDER BY updated_at LIMIT ? FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		models.DeliveryPending, channels, maxAttempts, models.DeliveryFailed, models.DeliveryPending, staleBefore, limit).
+		Scan(&deliveries).Error
+	return deliveries, err
+}
@@ -1,47 +1,50 @@
 package ticket
 
 import (
+	"ai-ticketing-backend/internal/pkg/config"
 	"ai-ticketing-backend/internal/pkg/db"
+	"ai-ticketing-backend/internal/pkg/storage"
+	"ai-ticketing-backend/services/ticket/realtime"
 	"ai-ticketing-backend/services/ticket/repository"
-	"fmt"
-	"os"
-
-	"github.com/joho/godotenv"
 )
 
-func Setup() (TicketService, *db.DB) {
-	_ = godotenv.Load()
+// Services groups everything the ticket HTTP server hands to its handlers
+type Services struct {
+	Tickets         TicketService
+	Attachments     AttachmentService
+	Comments        CommentService
+	Taxonomy        TaxonomyService
+	Classifications ClassificationService
+	Prompts         PromptService
+	Articles        ArticleService
+	Realtime        *realtime.Gateway // Started by the caller
+}
 
-	// DB setup
-	host := os.Getenv("DB_HOST")
-	if host == "" {
-		host = "postgres"
-	}
-	username := os.Getenv("DB_USERNAME")
-	if username == "" {
-		username = "ticket_user"
-	}
-	pass := os.Getenv("DB_PASSWORD")
-	if pass == "" {
-		pass = "ticket123"
-	}
-	dbname := os.Getenv("DB_DATABASE")
-	if dbname == "" {
-		dbname = "Ticket"
-	}
-	port := os.Getenv("DB_PORT")
-	if port == "" {
-		port = "5432"
+func Setup(cfg *config.Config) (*Services, *db.DB) {
+	dbConn, err := db.New(cfg.DB.DSN())
+	if err != nil {
+		panic(err)
 	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC", host, username, pass, dbname, port)
-
-	dbConn, err := db.New(dsn)
+	store, err := storage.New(cfg.Storage)
 	if err != nil {
 		panic(err)
 	}
 
 	repo := repository.NewTicketRepository(dbConn)
-	svc := NewTicketService(repo) // Same package—no import
-	return svc, dbConn
+	taxonomyRepo := repository.NewTaxonomyRepository(dbConn)
+	classificationRepo := repository.NewClassificationRepository(dbConn)
+	svc := NewTicketService(repo, taxonomyRepo, classificationRepo, cfg) // Same package—no import
+	attachments := NewAttachmentService(svc, repository.NewAttachmentRepository(dbConn), store, cfg)
+	comments := NewCommentService(svc, repository.NewCommentRepository(dbConn))
+	return &Services{
+		Tickets:         svc,
+		Attachments:     attachments,
+		Comments:        comments,
+		Taxonomy:        NewTaxonomyService(taxonomyRepo),
+		Classifications: NewClassificationService(svc, classificationRepo),
+		Prompts:         NewPromptService(repository.NewPromptRepository(dbConn)),
+		Articles:        NewArticleService(repository.NewArticleRepository(dbConn), taxonomyRepo),
+		Realtime:        realtime.NewGateway(repo, cfg.Tickets.StreamHistory),
+	}, dbConn
 }
@@ -0,0 +1,109 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket/repository"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const articleSearchLimit = 20
+
+type articleService struct {
+	repo     repository.ArticleRepository
+	taxonomy repository.TaxonomyRepository
+}
+
+func NewArticleService(repo repository.ArticleRepository, taxonomy repository.TaxonomyRepository) ArticleService {
+	return &articleService{repo: repo, taxonomy: taxonomy}
+}
+
+func (s *articleService) Create(req *models.CreateArticleRequest, authorID uuid.UUID) (*models.Article, error) {
+	article := &models.Article{
+		ID:        uuid.New(),
+		Title:     req.Title,
+		Body:      req.Body,
+		Tags:      req.Tags,
+		Published: req.Published,
+		AuthorID:  &authorID,
+	}
+	if err := s.setCategory(article, req.Category); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// Get returns an article; drafts are only visible to agents
+func (s *articleService) Get(id uuid.UUID, role string) (*models.Article, error) {
+	article, err := s.repo.Find(id)
+	if err != nil || (!article.Published && role != "agent") {
+		return nil, fmt.Errorf("article not found")
+	}
+	return article, nil
+}
+
+func (s *articleService) List(role string) ([]models.Article, error) {
+	return s.repo.List(role != "agent")
+}
+
+func (s *articleService) Search(query, role string) ([]models.Article, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("invalid search: query is empty")
+	}
+	return s.repo.Search(query, role != "agent", articleSearchLimit)
+}
+
+func (s *articleService) Update(id uuid.UUID, req *models.UpdateArticleRequest) (*models.Article, error) {
+	article, err := s.repo.Find(id)
+	if err != nil {
+		return nil, fmt.Errorf("article not found")
+	}
+	if req.Title != nil {
+		article.Title = *req.Title
+	}
+	if req.Body != nil {
+		article.Body = *req.Body
+	}
+	if req.Category != nil {
+		if err := s.setCategory(article, *req.Category); err != nil {
+			return nil, err
+		}
+	}
+	if req.Tags != nil {
+		article.Tags = *req.Tags
+	}
+	if req.Published != nil {
+		article.Published = *req.Published
+	}
+	if err := s.repo.Update(article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+func (s *articleService) Delete(id uuid.UUID) error {
+	if _, err := s.repo.Find(id); err != nil {
+		return fmt.Errorf("article not found")
+	}
+	return s.repo.Delete(id)
+}
+
+// setCategory validates an optional category against the taxonomy
+func (s *articleService) setCategory(article *models.Article, name string) error {
+	if name == "" {
+		article.Category = ""
+		return nil
+	}
+	category, err := s.taxonomy.FindCategoryByName(name)
+	if err != nil {
+		return fmt.Errorf("invalid category %q", name)
+	}
+	article.Category = category.Name
+	return nil
+}
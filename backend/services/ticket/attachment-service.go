@@ -0,0 +1,193 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"ai-ticketing-backend/internal/pkg/storage"
+	"ai-ticketing-backend/services/ticket/repository"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAttachmentTooLarge       = errors.New("attachment exceeds the maximum upload size")
+	ErrUnsupportedMediaType     = errors.New("attachment type is not allowed")
+	ErrInvalidDownloadSignature = errors.New("invalid or expired download link")
+)
+
+type attachmentService struct {
+	tickets    TicketService
+	repo       repository.AttachmentRepository
+	store      storage.BlobStore
+	cache      *redis.Client
+	maxBytes   int64
+	allowed    map[string]bool
+	signingKey []byte
+	urlTTL     time.Duration
+}
+
+func NewAttachmentService(tickets TicketService, repo repository.AttachmentRepository, store storage.BlobStore, cfg *config.Config) AttachmentService {
+	allowed := map[string]bool{}
+	for _, t := range cfg.Storage.AllowedTypes {
+		allowed[strings.ToLower(t)] = true
+	}
+	key := cfg.Storage.SigningKey
+	if key == "" {
+		key = cfg.JWT.Secret
+	}
+	return &attachmentService{
+		tickets:    tickets,
+		repo:       repo,
+		store:      store,
+		cache:      redis.New(cfg.Redis.Addr),
+		maxBytes:   int64(cfg.Storage.MaxUploadBytes),
+		allowed:    allowed,
+		signingKey: []byte(key),
+		urlTTL:     cfg.Storage.URLTTL,
+	}
+}
+
+// MaxUploadBytes is used by handlers to cap the request body
+func (s *attachmentService) MaxUploadBytes() int64 {
+	return s.maxBytes
+}
+
+func (s *attachmentService) Upload(ticketID, userID uuid.UUID, role string, file *multipart.FileHeader) (*models.Attachment, error) {
+	ticket, err := s.tickets.GetByID(ticketID, ownerFilter(userID, role))
+	if err != nil {
+		return nil, err
+	}
+	if file.Size > s.maxBytes {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Sniff the real type instead of trusting the client's header
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := detectContentType(head, file.Filename)
+	if !s.allowed[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, contentType)
+	}
+
+	attachment := &models.Attachment{
+		ID:          uuid.New(),
+		TicketID:    ticket.ID,
+		UploaderID:  userID,
+		Filename:    filepath.Base(file.Filename),
+		ContentType: contentType,
+		Size:        file.Size,
+	}
+	attachment.StorageKey = "tickets/" + ticket.ID.String() + "/" + attachment.ID.String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	body := io.MultiReader(bytes.NewReader(head), f)
+	if err := s.store.Put(ctx, attachment.StorageKey, body, file.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if err := s.repo.Create(attachment); err != nil {
+		s.store.Delete(ctx, attachment.StorageKey) // Don't leave orphaned blobs
+		return nil, err
+	}
+
+	// The cached ticket embeds its attachments
+	s.cache.CacheDel(ctx, "ticket:"+ticket.ID.String())
+	log.Printf("Stored attachment %s (%s, %d bytes) on ticket %s", attachment.ID, contentType, file.Size, ticket.ID)
+	return attachment, nil
+}
+
+func (s *attachmentService) List(ticketID, userID uuid.UUID, role string) ([]models.Attachment, error) {
+	if _, err := s.tickets.GetByID(ticketID, ownerFilter(userID, role)); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTicket(ticketID)
+}
+
+func (s *attachmentService) SignedURL(ticketID, attachmentID, userID uuid.UUID, role string) (*models.AttachmentURLResponse, error) {
+	if _, err := s.tickets.GetByID(ticketID, ownerFilter(userID, role)); err != nil {
+		return nil, err
+	}
+	attachment, err := s.repo.FindByID(attachmentID)
+	if err != nil || attachment.TicketID != ticketID {
+		return nil, fmt.Errorf("attachment not found")
+	}
+
+	expires := time.Now().Add(s.urlTTL).Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	url := "/api/v1/attachments/" + attachment.ID.String() + "/download?expires=" + exp + "&sig=" + s.sign(attachment.ID, exp)
+	return &models.AttachmentURLResponse{URL: url, ExpiresAt: expires}, nil
+}
+
+func (s *attachmentService) Open(attachmentID uuid.UUID, expires, sig string) (*models.Attachment, io.ReadCloser, error) {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return nil, nil, ErrInvalidDownloadSignature
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.sign(attachmentID, expires))) {
+		return nil, nil, ErrInvalidDownloadSignature
+	}
+
+	attachment, err := s.repo.FindByID(attachmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("attachment not found")
+	}
+	body, err := s.store.Get(context.Background(), attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, body, nil
+}
+
+// ownerFilter mirrors the GetByID handler: agents pass uuid.Nil to skip the ownership check
+func ownerFilter(userID uuid.UUID, role string) uuid.UUID {
+	if role == "agent" {
+		return uuid.Nil
+	}
+	return userID
+}
+
+// sign binds the attachment ID and expiry so neither can be swapped
+func (s *attachmentService) sign(attachmentID uuid.UUID, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(attachmentID.String() + "|" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// detectContentType sniffs the bytes and falls back to the extension for
+// text formats (logs, JSON) that sniff as plain text.
+func detectContentType(head []byte, filename string) string {
+	ct := http.DetectContentType(head)
+	if i := strings.Index(ct, ";"); i >= 0 {
+		ct = ct[:i]
+	}
+	if ct == "text/plain" && strings.EqualFold(filepath.Ext(filename), ".json") {
+		return "application/json"
+	}
+	return strings.ToLower(strings.TrimSpace(ct))
+}
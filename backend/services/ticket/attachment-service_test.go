@@ -0,0 +1,165 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeTickets answers GetByID like the ticket service: a non-nil userID
+// must own the ticket
+type fakeTickets struct {
+	TicketService
+	owners map[uuid.UUID]uuid.UUID
+}
+
+func (f *fakeTickets) GetByID(id, userID uuid.UUID) (*models.Ticket, error) {
+	owner, ok := f.owners[id]
+	if !ok {
+		return nil, fmt.Errorf("ticket not found")
+	}
+	if userID != uuid.Nil && owner != userID {
+		return nil, fmt.Errorf("unauthorized: not your ticket")
+	}
+	return &models.Ticket{ID: id, UserID: owner}, nil
+}
+
+type fakeAttachments struct {
+	byID map[uuid.UUID]*models.Attachment
+}
+
+func (f *fakeAttachments) Create(a *models.Attachment) error { return nil }
+
+func (f *fakeAttachments) FindByID(id uuid.UUID) (*models.Attachment, error) {
+	if a, ok := f.byID[id]; ok {
+		return a, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (f *fakeAttachments) ListByTicket(ticketID uuid.UUID) ([]models.Attachment, error) {
+	return nil, nil
+}
+
+type fakeStore struct {
+	storage.BlobStore
+}
+
+func (fakeStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("contents of " + key)), nil
+}
+
+func TestAttachmentSignedURL(t *testing.T) {
+	owner, other, agent := uuid.New(), uuid.New(), uuid.New()
+	ticketID, otherTicketID := uuid.New(), uuid.New()
+	attachment := &models.Attachment{ID: uuid.New(), TicketID: ticketID, StorageKey: "k1"}
+	svc := &attachmentService{
+		tickets:    &fakeTickets{owners: map[uuid.UUID]uuid.UUID{ticketID: owner, otherTicketID: other}},
+		repo:       &fakeAttachments{byID: map[uuid.UUID]*models.Attachment{attachment.ID: attachment}},
+		store:      fakeStore{},
+		signingKey: []byte("key"),
+		urlTTL:     15 * time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		ticketID uuid.UUID
+		userID   uuid.UUID
+		role     string
+		wantErr  string
+	}{
+		{"owner", ticketID, owner, "customer", ""},
+		{"agent", ticketID, agent, "agent", ""},
+		{"another customer", ticketID, other, "customer", "unauthorized"},
+		{"attachment of another ticket", otherTicketID, other, "customer", "attachment not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := svc.SignedURL(tt.ticketID, attachment.ID, tt.userID, tt.role)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SignedURL error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if until := time.Until(resp.ExpiresAt); until <= 14*time.Minute || until > 15*time.Minute {
+				t.Errorf("link expires in %s, want about 15m", until)
+			}
+			u, err := url.Parse(resp.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "/api/v1/attachments/" + attachment.ID.String() + "/download"; u.Path != want {
+				t.Errorf("path = %q, want %q", u.Path, want)
+			}
+			got, body, err := svc.Open(attachment.ID, u.Query().Get("expires"), u.Query().Get("sig"))
+			if err != nil {
+				t.Fatalf("Open of a freshly signed link: %v", err)
+			}
+			body.Close()
+			if got.ID != attachment.ID {
+				t.Errorf("Open returned attachment %s, want %s", got.ID, attachment.ID)
+			}
+		})
+	}
+}
+
+func TestAttachmentOpenSignature(t *testing.T) {
+	id, otherID := uuid.New(), uuid.New()
+	svc := &attachmentService{
+		repo: &fakeAttachments{byID: map[uuid.UUID]*models.Attachment{
+			id:      {ID: id, StorageKey: "k1"},
+			otherID: {ID: otherID, StorageKey: "k2"},
+		}},
+		store:      fakeStore{},
+		signingKey: []byte("key"),
+	}
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	later := strconv.FormatInt(time.Now().Add(2*time.Hour).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	otherKey := &attachmentService{signingKey: []byte("other key")}
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		expires string
+		sig     string
+		valid   bool
+	}{
+		{"valid", id, future, svc.sign(id, future), true},
+		{"signed for another attachment", otherID, future, svc.sign(id, future), false},
+		{"expiry extended", id, later, svc.sign(id, future), false},
+		{"expired", id, past, svc.sign(id, past), false},
+		{"expiry not a number", id, "tomorrow", svc.sign(id, "tomorrow"), false},
+		{"signed with another key", id, future, otherKey.sign(id, future), false},
+		{"tampered signature", id, future, strings.Repeat("0", 64), false},
+		{"no signature", id, future, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body, err := svc.Open(tt.id, tt.expires, tt.sig)
+			if body != nil {
+				body.Close()
+			}
+			switch {
+			case tt.valid && err != nil:
+				t.Errorf("Open = %v, want the attachment", err)
+			case !tt.valid && !errors.Is(err, ErrInvalidDownloadSignature):
+				t.Errorf("Open = %v, want ErrInvalidDownloadSignature", err)
+			}
+		})
+	}
+}
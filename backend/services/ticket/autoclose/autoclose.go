@@ -0,0 +1,35 @@
+package autoclose
+
+import (
+	"ai-ticketing-backend/services/ticket"
+	"log"
+	"time"
+)
+
+// Start closes auto-resolved tickets whose customer hasn't replied within
+// after of the ticket starting to wait. It checks every tenth of that window,
+// between 1 minute and 1 hour. Every replica may run it: each ticket is
+// closed by a single conditional update.
+func Start(svc ticket.TicketService, after time.Duration) {
+	interval := after / 10
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	if interval > time.Hour {
+		interval = time.Hour
+	}
+	log.Printf("Auto-closing unanswered auto-resolved tickets after %s (checking every %s)", after, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		closed, err := svc.CloseStale(after)
+		if err != nil {
+			log.Printf("Auto-close failed: %v", err)
+			continue
+		}
+		if closed > 0 {
+			log.Printf("Auto-closed %d tickets", closed)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Report builds the accuracy report over runs created in [from, to).
+// The correct label for a run is what the agent settled on, or the AI's own
+// value when it was auto-applied or accepted and never changed.
+func (s *classificationService) Report(from, to time.Time, interval string) (*models.ClassificationReport, error) {
+	if interval != "day" && interval != "week" {
+		return nil, fmt.Errorf("invalid interval %q: use day or week", interval)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid range: from must be before to")
+	}
+	runs, err := s.repo.ListBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]*reportBuilder{}
+	var keys []string
+	for i := range runs {
+		run := &runs[i]
+		key := run.Model + "\x00" + run.PromptVersion
+		b, ok := groups[key]
+		if !ok {
+			b = newReportBuilder(run.Model, run.PromptVersion)
+			groups[key] = b
+			keys = append(keys, key)
+		}
+		b.add(run, periodStart(run.CreatedAt, interval))
+	}
+	sort.Strings(keys)
+
+	report := &models.ClassificationReport{From: from, To: to, Interval: interval, Groups: []models.ClassificationReportGroup{}}
+	for _, key := range keys {
+		report.Groups = append(report.Groups, groups[key].build())
+	}
+	return report, nil
+}
+
+// overridden reports whether an agent rejected or changed the AI's values
+func overridden(run *models.AIClassification) bool {
+	switch run.Status {
+	case models.ClassificationEdited, models.ClassificationRejected:
+		return true
+	}
+	return run.FinalCategory != "" && (run.FinalCategory != run.Category || run.FinalPriority != run.Priority)
+}
+
+// actualLabels returns the correct category and priority, or ok=false if
+// nobody has confirmed or corrected the run yet
+func actualLabels(run *models.AIClassification) (category, priority string, ok bool) {
+	if run.FinalCategory != "" {
+		return run.FinalCategory, run.FinalPriority, true
+	}
+	switch run.Status {
+	case models.ClassificationAutoApplied, models.ClassificationAccepted:
+		return run.Category, run.Priority, true
+	}
+	return "", "", false
+}
+
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == "week" {
+		// Weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+type reportBuilder struct {
+	group      models.ClassificationReportGroup
+	correct    int
+	series     map[time.Time]*models.OverridePoint
+	categories map[string]map[string]int
+	priorities map[string]map[string]int
+}
+
+func newReportBuilder(model, promptVersion string) *reportBuilder {
+	return &reportBuilder{
+		group:      models.ClassificationReportGroup{Model: model, PromptVersion: promptVersion},
+		series:     map[time.Time]*models.OverridePoint{},
+		categories: map[string]map[string]int{},
+		priorities: map[string]map[string]int{},
+	}
+}
+
+func (b *reportBuilder) add(run *models.AIClassification, period time.Time) {
+	b.group.Runs++
+	if run.Status == models.ClassificationPending || run.Status == models.ClassificationSuperseded {
+		return // Undecided runs don't count towards overrides or accuracy
+	}
+
+	point, ok := b.series[period]
+	if !ok {
+		point = &models.OverridePoint{Period: period}
+		b.series[period] = point
+	}
+	point.Runs++
+	if overridden(run) {
+		b.group.Overridden++
+		point.Overridden++
+	}
+
+	category, priority, ok := actualLabels(run)
+	if !ok {
+		return
+	}
+	b.group.Labeled++
+	if category == run.Category {
+		b.correct++
+	}
+	count(b.categories, category, run.Category)
+	count(b.priorities, priority, run.Priority)
+}
+
+func count(confusion map[string]map[string]int, actual, predicted string) {
+	if confusion[actual] == nil {
+		confusion[actual] = map[string]int{}
+	}
+	confusion[actual][predicted]++
+}
+
+func (b *reportBuilder) build() models.ClassificationReportGroup {
+	g := b.group
+	decided := 0
+	for _, p := range b.series {
+		decided += p.Runs
+		p.Rate = ratio(p.Overridden, p.Runs)
+		g.OverrideSeries = append(g.OverrideSeries, *p)
+	}
+	sort.Slice(g.OverrideSeries, func(i, j int) bool { return g.OverrideSeries[i].Period.Before(g.OverrideSeries[j].Period) })
+	g.OverrideRate = ratio(g.Overridden, decided)
+	g.Accuracy = ratio(b.correct, g.Labeled)
+	g.Categories, g.CategoryConfusion = classMetrics(b.categories), b.categories
+	g.Priorities, g.PriorityConfusion = classMetrics(b.priorities), b.priorities
+	return g
+}
+
+// classMetrics derives per-class precision and recall from a confusion matrix
+func classMetrics(confusion map[string]map[string]int) []models.ClassMetrics {
+	byName := map[string]*models.ClassMetrics{}
+	get := func(name string) *models.ClassMetrics {
+		if byName[name] == nil {
+			byName[name] = &models.ClassMetrics{Name: name}
+		}
+		return byName[name]
+	}
+	correct := map[string]int{}
+	for actual, row := range confusion {
+		for predicted, n := range row {
+			get(actual).Support += n
+			get(predicted).Predicted += n
+			if actual == predicted {
+				correct[actual] += n
+			}
+		}
+	}
+
+	metrics := []models.ClassMetrics{}
+	for name, m := range byName {
+		m.Precision = ratio(correct[name], m.Predicted)
+		m.Recall = ratio(correct[name], m.Support)
+		metrics = append(metrics, *m)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	return metrics
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
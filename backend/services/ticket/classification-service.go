@@ -0,0 +1,135 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket/repository"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+type classificationService struct {
+	tickets TicketService
+	repo    repository.ClassificationRepository
+}
+
+func NewClassificationService(tickets TicketService, repo repository.ClassificationRepository) ClassificationService {
+	return &classificationService{tickets: tickets, repo: repo}
+}
+
+func (s *classificationService) ListByTicket(ticketID uuid.UUID) ([]models.AIClassification, error) {
+	return s.repo.ListByTicket(ticketID)
+}
+
+func (s *classificationService) ListPending() ([]models.AIClassification, error) {
+	return s.repo.ListPending()
+}
+
+// Review applies an agent's accept/edit/reject decision. The ticket goes
+// through TicketService.ApplyReview so taxonomy validation, cache
+// invalidation and status events behave exactly like a manual agent update;
+// corrections are recorded here only, against the reviewed run.
+func (s *classificationService) Review(ticketID, runID, agentID uuid.UUID, req *models.ReviewClassificationRequest) (*models.AIClassification, error) {
+	run, err := s.repo.FindByID(runID)
+	if err != nil || run.TicketID != ticketID {
+		return nil, fmt.Errorf("classification not found")
+	}
+	if run.ReviewedAt != nil {
+		return nil, fmt.Errorf("invalid review: classification was already %s", run.Status)
+	}
+	if run.Status == models.ClassificationSuperseded {
+		return nil, fmt.Errorf("invalid review: %w", repository.ErrSuperseded)
+	}
+
+	category, priority, suggestion := run.Category, run.Priority, run.Suggestion
+	status := "classified"
+	switch req.Action {
+	case "accept":
+		if !run.Valid {
+			return nil, fmt.Errorf("invalid review: output failed validation, edit it instead")
+		}
+	case "edit":
+		if req.Category != nil {
+			category = *req.Category
+		}
+		if req.Priority != nil {
+			priority = *req.Priority
+		}
+		if req.Suggestion != nil {
+			suggestion = *req.Suggestion
+		}
+	case "reject":
+		status = "open" // Back to the normal queue without AI values
+	}
+
+	// Claimed first, so a stale or concurrent review can't overwrite the ticket
+	if err := s.repo.ClaimReview(run, agentID); err != nil {
+		if errors.Is(err, repository.ErrSuperseded) || errors.Is(err, repository.ErrReviewed) {
+			return nil, fmt.Errorf("invalid review: %w", err)
+		}
+		return nil, err
+	}
+	update := &models.UpdateTicketRequest{Status: &status}
+	if req.Action != "reject" {
+		update.Category, update.Priority, update.Suggestion = &category, &priority, &suggestion
+	}
+	ticket, err := s.tickets.ApplyReview(ticketID, update, agentID)
+	if err != nil {
+		if rerr := s.repo.ReleaseReview(run); rerr != nil {
+			log.Printf("Failed to release review of classification %s: %v", run.ID, rerr)
+		}
+		return nil, err
+	}
+
+	var feedback []models.ClassificationFeedback
+	switch req.Action {
+	case "accept":
+		run.Status = models.ClassificationAccepted
+	case "edit":
+		run.Status = models.ClassificationEdited
+		// Compare the canonical names the ticket update resolved
+		feedback = correctionFeedback(run, ticket.Category, ticket.Priority, agentID)
+	case "reject":
+		run.Status = models.ClassificationRejected
+	}
+	if req.Action != "reject" {
+		run.FinalCategory, run.FinalPriority, run.FinalSuggestion = ticket.Category, ticket.Priority, ticket.Suggestion
+	}
+
+	if err := s.repo.Update(run); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateFeedback(feedback); err != nil {
+		log.Printf("Failed to record feedback for classification %s: %v", run.ID, err)
+	}
+	return run, nil
+}
+
+// correctionFeedback lists the fields where category/priority differ from
+// what the run currently says, recorded against the AI's original value.
+func correctionFeedback(run *models.AIClassification, category, priority string, agentID uuid.UUID) []models.ClassificationFeedback {
+	currentCategory, currentPriority := run.Category, run.Priority
+	if run.FinalCategory != "" {
+		currentCategory, currentPriority = run.FinalCategory, run.FinalPriority
+	}
+
+	var feedback []models.ClassificationFeedback
+	add := func(field, aiValue, current, value string) {
+		if value == "" || value == current {
+			return
+		}
+		feedback = append(feedback, models.ClassificationFeedback{
+			ClassificationID: run.ID,
+			TicketID:         run.TicketID,
+			AgentID:          agentID,
+			Field:            field,
+			AIValue:          aiValue,
+			AgentValue:       value,
+		})
+	}
+	add("category", run.Category, currentCategory, category)
+	add("priority", run.Priority, currentPriority, priority)
+	return feedback
+}
@@ -0,0 +1,110 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket/repository"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type commentService struct {
+	tickets TicketService
+	repo    repository.CommentRepository
+}
+
+func NewCommentService(tickets TicketService, repo repository.CommentRepository) CommentService {
+	return &commentService{tickets: tickets, repo: repo}
+}
+
+func (s *commentService) Add(ticketID, userID uuid.UUID, role string, req *models.CreateCommentRequest) (*models.Comment, error) {
+	ticket, err := s.tickets.GetByID(ticketID, ownerFilter(userID, role))
+	if err != nil {
+		return nil, err
+	}
+
+	public := true
+	if req.Public != nil {
+		public = *req.Public
+	}
+	if !public && role != "agent" {
+		return nil, fmt.Errorf("unauthorized: only agents can add internal notes")
+	}
+	if role != "agent" {
+		role = "customer"
+	}
+
+	var draft *models.ReplyDraft
+	if req.DraftID != nil {
+		if draft, err = s.findDraft(*req.DraftID, ticketID, role, public); err != nil {
+			return nil, err
+		}
+	}
+
+	comment := &models.Comment{
+		TicketID:   ticketID,
+		AuthorID:   userID,
+		AuthorRole: role,
+		Body:       req.Body,
+		Public:     public,
+	}
+	if err := s.repo.Create(comment); err != nil {
+		return nil, err
+	}
+	s.tickets.CommentAdded(ticket, comment)
+	if draft != nil {
+		s.draftSent(draft, comment)
+	}
+	if role == "customer" && public {
+		// A reply to an AI answer means it didn't solve the problem
+		if err := s.tickets.Reopen(ticketID); err != nil {
+			log.Printf("Failed to reopen ticket %s: %v", ticketID, err)
+		}
+	}
+	return comment, nil
+}
+
+// findDraft loads the AI reply draft a comment is sent from; only agents
+// send drafts, and only as public replies on the ticket they were written for
+func (s *commentService) findDraft(id, ticketID uuid.UUID, role string, public bool) (*models.ReplyDraft, error) {
+	if role != "agent" {
+		return nil, fmt.Errorf("unauthorized: only agents can send reply drafts")
+	}
+	if !public {
+		return nil, fmt.Errorf("invalid request: reply drafts are sent as public comments")
+	}
+	draft, err := s.repo.FindDraft(id)
+	if err != nil || draft.TicketID != ticketID {
+		return nil, fmt.Errorf("draft not found")
+	}
+	if draft.CommentID != nil {
+		return nil, fmt.Errorf("invalid request: draft was already sent")
+	}
+	return draft, nil
+}
+
+// draftSent records whether the agent sent the draft as written or edited it
+func (s *commentService) draftSent(draft *models.ReplyDraft, comment *models.Comment) {
+	now := time.Now()
+	draft.CommentID = &comment.ID
+	draft.SentAt = &now
+	draft.Outcome = models.DraftEdited
+	// Whitespace changes don't count as edits
+	if strings.Join(strings.Fields(draft.Body), " ") == strings.Join(strings.Fields(comment.Body), " ") {
+		draft.Outcome = models.DraftUsed
+	}
+	if err := s.repo.UpdateDraft(draft); err != nil {
+		log.Printf("Failed to record draft %s as %s: %v", draft.ID, draft.Outcome, err)
+	}
+}
+
+func (s *commentService) List(ticketID, userID uuid.UUID, role string) ([]models.Comment, error) {
+	if _, err := s.tickets.GetByID(ticketID, ownerFilter(userID, role)); err != nil {
+		return nil, err
+	}
+	// Customers never see internal notes
+	return s.repo.ListByTicket(ticketID, role != "agent")
+}
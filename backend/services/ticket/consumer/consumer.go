@@ -2,35 +2,29 @@ package consumer
 
 import (
 	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
-func StartConsumer() {
-	kafkaBroker := os.Getenv("KAFKA_BROKER")
-	if kafkaBroker == "" {
-		// Default to Kafka container hostname when running in Docker
-		kafkaBroker = "kafka:9092"
-	}
-	log.Printf("KAFKA_BROKER: %s", kafkaBroker)
+func StartConsumer(cfg config.KafkaConfig) {
+	log.Printf("KAFKA_BROKER: %s", cfg.Brokers)
 
 	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  strings.Split(kafkaBroker, ","),
+		Brokers:  cfg.Brokers,
 		GroupID:  "ticket-consumer-group",
-		Topic:    "ticket-events",
+		Topic:    cfg.Topic,
 		MinBytes: 10e3, // 10KB
 		MaxBytes: 10e6, // 10MB
 	})
 
 	defer reader.Close()
-	fmt.Println("Consuming from topic:", "ticket-events")
+	fmt.Println("Consuming from topic:", cfg.Topic)
 
 	ctx := context.Background()
 
@@ -42,6 +36,9 @@ func StartConsumer() {
 			continue
 		}
 
+		if models.EventType(msg.Value) != models.EventTicketCreated {
+			continue
+		}
 		var event models.TicketCreatedEvent
 		if err := json.Unmarshal(msg.Value, &event); err != nil {
 			log.Printf("Failed to unmarshal event: %v\n", err)
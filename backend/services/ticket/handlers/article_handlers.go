@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ArticleHandlers struct {
+	svc ticket.ArticleService
+}
+
+func NewArticleHandlers(svc ticket.ArticleService) *ArticleHandlers {
+	return &ArticleHandlers{svc: svc}
+}
+
+// List for GET /api/v1/articles and GET /api/v1/agent/articles (agents also see drafts)
+func (h *ArticleHandlers) List(c *gin.Context) {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	var (
+		articles []models.Article
+		err      error
+	)
+	if q := c.Query("q"); q != "" {
+		articles, err = h.svc.Search(q, roleStr)
+	} else {
+		articles, err = h.svc.List(roleStr)
+	}
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, articles)
+}
+
+// Get for GET /api/v1/articles/:id and GET /api/v1/agent/articles/:id
+func (h *ArticleHandlers) Get(c *gin.Context) {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	article, err := h.svc.Get(id, roleStr)
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
+// Create for POST /api/v1/agent/articles
+func (h *ArticleHandlers) Create(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	var req models.CreateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	article, err := h.svc.Create(&req, userID)
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, article)
+}
+
+// Update for PUT /api/v1/agent/articles/:id
+func (h *ArticleHandlers) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	var req models.UpdateArticleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	article, err := h.svc.Update(id, &req)
+	if err != nil {
+		writeArticleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, article)
+}
+
+// Delete for DELETE /api/v1/agent/articles/:id
+func (h *ArticleHandlers) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	if err := h.svc.Delete(id); err != nil {
+		writeArticleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func writeArticleError(c *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "invalid"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"ai-ticketing-backend/services/ticket"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AttachmentHandlers struct {
+	svc ticket.AttachmentService
+}
+
+func NewAttachmentHandlers(svc ticket.AttachmentService) *AttachmentHandlers {
+	return &AttachmentHandlers{svc: svc}
+}
+
+// Upload for POST /api/v1/tickets/:id/attachments (multipart field "file")
+func (h *AttachmentHandlers) Upload(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+	role := c.GetString("role")
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	// Leave headroom for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.svc.MaxUploadBytes()+1<<20)
+	file, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": ticket.ErrAttachmentTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field 'file' is required"})
+		return
+	}
+
+	attachment, err := h.svc.Upload(id, userID, role, file)
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// List for GET /api/v1/tickets/:id/attachments
+func (h *AttachmentHandlers) List(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	attachments, err := h.svc.List(id, userID, c.GetString("role"))
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, attachments)
+}
+
+// SignedURL for GET /api/v1/tickets/:id/attachments/:attachment_id/url
+func (h *AttachmentHandlers) SignedURL(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment ID"})
+		return
+	}
+
+	resp, err := h.svc.SignedURL(id, attachmentID, userID, c.GetString("role"))
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Download for GET /api/v1/attachments/:attachment_id/download?expires=&sig=
+// No JWT here: the signature from SignedURL is the authorization.
+func (h *AttachmentHandlers) Download(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment ID"})
+		return
+	}
+
+	attachment, body, err := h.svc.Open(attachmentID, c.Query("expires"), c.Query("sig"))
+	if err != nil {
+		writeAttachmentError(c, err)
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
+
+func writeAttachmentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ticket.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, ticket.ErrUnsupportedMediaType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, ticket.ErrInvalidDownloadSignature):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "unauthorized"):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
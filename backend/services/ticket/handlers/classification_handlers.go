@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ClassificationHandlers struct {
+	svc ticket.ClassificationService
+}
+
+func NewClassificationHandlers(svc ticket.ClassificationService) *ClassificationHandlers {
+	return &ClassificationHandlers{svc: svc}
+}
+
+// ListByTicket for GET /api/v1/agent/tickets/:id/classifications
+func (h *ClassificationHandlers) ListByTicket(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	runs, err := h.svc.ListByTicket(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+// ListPending for GET /api/v1/agent/classifications/pending
+func (h *ClassificationHandlers) ListPending(c *gin.Context) {
+	runs, err := h.svc.ListPending()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+// Review for POST /api/v1/agent/tickets/:id/classifications/:classification_id/review
+func (h *ClassificationHandlers) Review(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	runID, err := uuid.Parse(c.Param("classification_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid classification ID"})
+		return
+	}
+
+	var req models.ReviewClassificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	run, err := h.svc.Review(id, runID, userID, &req)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "invalid"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// Report for GET /api/v1/agent/reports/classification?from=2006-01-02&to=2006-01-02&interval=day|week
+// Defaults to the last 30 days by day; to is inclusive.
+func (h *ClassificationHandlers) Report(c *gin.Context) {
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date, use YYYY-MM-DD"})
+			return
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	from := to.AddDate(0, 0, -30)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date, use YYYY-MM-DD"})
+			return
+		}
+		from = t
+	}
+
+	report, err := h.svc.Report(from, to, c.DefaultQuery("interval", "day"))
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
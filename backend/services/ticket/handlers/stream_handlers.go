@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"ai-ticketing-backend/services/ticket/realtime"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StreamHandlers struct {
+	gateway   *realtime.Gateway
+	heartbeat time.Duration
+}
+
+func NewStreamHandlers(gateway *realtime.Gateway, heartbeat time.Duration) *StreamHandlers {
+	return &StreamHandlers{gateway: gateway, heartbeat: heartbeat}
+}
+
+// Stream for GET /api/v1/stream: ticket updates and the user's in-app
+// notifications as server-sent events.
+// Agents choose ?scope=queue or all (the default); customers get their own
+// tickets. Reconnecting clients send Last-Event-ID (or ?last_event_id=) and
+// get what they missed, or a "reset" event when that is too old.
+func (h *StreamHandlers) Stream(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+	filter := realtime.Filter{UserID: userID, Role: c.GetString("role"), Scope: c.DefaultQuery("scope", realtime.ScopeAll)}
+	if filter.Scope != realtime.ScopeAll && filter.Scope != realtime.ScopeQueue {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scope: use queue or all"})
+		return
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub, missed, ok := h.gateway.Subscribe(filter, lastEventID)
+	defer h.gateway.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Don't let a proxy hold the stream back
+	c.Status(http.StatusOK)
+	if !ok {
+		fmt.Fprint(c.Writer, "event: reset\ndata: {}\n\n")
+	}
+	for i := range missed {
+		writeEvent(c.Writer, &missed[i])
+	}
+	c.Writer.Flush()
+
+	interval := h.heartbeat
+	if interval <= 0 {
+		interval = 25 * time.Second
+	}
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case u, open := <-sub.Updates():
+			if !open {
+				return // Fell behind; the client resumes with Last-Event-ID
+			}
+			writeEvent(c.Writer, &u)
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes e with its id, so the browser sends it back as
+// Last-Event-ID
+func writeEvent(w io.Writer, e *realtime.Event) {
+	data, _ := json.Marshal(e.Data)
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Name, data)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TaxonomyHandlers struct {
+	svc ticket.TaxonomyService
+}
+
+func NewTaxonomyHandlers(svc ticket.TaxonomyService) *TaxonomyHandlers {
+	return &TaxonomyHandlers{svc: svc}
+}
+
+// ListCategories for GET /api/v1/taxonomy/categories (?include_inactive=true for admins)
+func (h *TaxonomyHandlers) ListCategories(c *gin.Context) {
+	categories, err := h.svc.ListCategories(includeInactive(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateCategory for POST /api/v1/admin/taxonomy/categories
+func (h *TaxonomyHandlers) CreateCategory(c *gin.Context) {
+	var req models.TaxonomyEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	category, err := h.svc.CreateCategory(&req)
+	if err != nil {
+		writeTaxonomyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// UpdateCategory for PUT /api/v1/admin/taxonomy/categories/:id
+func (h *TaxonomyHandlers) UpdateCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	var req models.UpdateTaxonomyEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	category, err := h.svc.UpdateCategory(id, &req)
+	if err != nil {
+		writeTaxonomyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// DeleteCategory for DELETE /api/v1/admin/taxonomy/categories/:id
+// Categories are deactivated, not removed, so existing tickets keep a valid value.
+func (h *TaxonomyHandlers) DeleteCategory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	inactive := false
+	category, err := h.svc.UpdateCategory(id, &models.UpdateTaxonomyEntryRequest{Active: &inactive})
+	if err != nil {
+		writeTaxonomyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// ListPriorities for GET /api/v1/taxonomy/priorities (?include_inactive=true for admins)
+func (h *TaxonomyHandlers) ListPriorities(c *gin.Context) {
+	priorities, err := h.svc.ListPriorities(includeInactive(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, priorities)
+}
+
+// CreatePriority for POST /api/v1/admin/taxonomy/priorities
+func (h *TaxonomyHandlers) CreatePriority(c *gin.Context) {
+	var req models.TaxonomyEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	priority, err := h.svc.CreatePriority(&req)
+	if err != nil {
+		writeTaxonomyError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, priority)
+}
+
+// UpdatePriority for PUT /api/v1/admin/taxonomy/priorities/:id
+func (h *TaxonomyHandlers) UpdatePriority(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	var req models.UpdateTaxonomyEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	priority, err := h.svc.UpdatePriority(id, &req)
+	if err != nil {
+		writeTaxonomyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, priority)
+}
+
+// DeletePriority for DELETE /api/v1/admin/taxonomy/priorities/:id (deactivates)
+func (h *TaxonomyHandlers) DeletePriority(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	inactive := false
+	priority, err := h.svc.UpdatePriority(id, &models.UpdateTaxonomyEntryRequest{Active: &inactive})
+	if err != nil {
+		writeTaxonomyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, priority)
+}
+
+func includeInactive(c *gin.Context) bool {
+	return c.Query("include_inactive") == "true" && c.GetString("role") == "admin"
+}
+
+func writeTaxonomyError(c *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "invalid"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"ai-ticketing-backend/internal/models"
 	"ai-ticketing-backend/services/ticket"
+	"log"
 	"net/http"
 	"strings"
 
@@ -70,9 +71,24 @@ func (h *TicketHandlers) GetByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "ticket not found"})
 		return
 	}
+	// Agents also get the AI summary of the thread, which can cover internal notes
+	if role == "agent" {
+		summary, err := h.svc.GetSummary(id)
+		if err != nil {
+			log.Printf("Failed to load summary for ticket %s: %v", id, err)
+		}
+		c.JSON(http.StatusOK, agentTicket{Ticket: ticket, Summary: summary})
+		return
+	}
 	c.JSON(http.StatusOK, ticket)
 }
 
+// agentTicket is a ticket as agents see it
+type agentTicket struct {
+	*models.Ticket
+	Summary *models.TicketSummary `json:"summary,omitempty"`
+}
+
 // ListByUserHandler for GET /api/v1/tickets
 func (h *TicketHandlers) ListByUser(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
@@ -100,6 +116,54 @@ func (h *TicketHandlers) ListAll(c *gin.Context) {
 	c.JSON(http.StatusOK, tickets)
 }
 
+// ConfirmResolution for POST /api/v1/tickets/:id/confirm
+// The customer accepts the AI's answer and the ticket closes.
+func (h *TicketHandlers) ConfirmResolution(c *gin.Context) {
+	userIDStr, _ := c.Get("user_id")
+	userID, _ := userIDStr.(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	ticket, err := h.svc.ConfirmResolution(id, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "unauthorized"):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": "ticket not found"})
+		case strings.Contains(err.Error(), "invalid"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, ticket)
+}
+
+// Similar for GET /api/v1/agent/tickets/:id/similar
+// Likely duplicates and related tickets found by the AI service.
+func (h *TicketHandlers) Similar(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	similar, err := h.svc.ListSimilar(id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "ticket not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, similar)
+}
+
 // UpdateHandler for PUT /api/v1/tickets/:id
 func (h *TicketHandlers) Update(c *gin.Context) {
 	userIDStr, _ := c.Get("user_id")
@@ -131,6 +195,10 @@ func (h *TicketHandlers) Update(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if strings.Contains(err.Error(), "invalid") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -2,6 +2,10 @@ package ticket
 
 import (
 	"ai-ticketing-backend/internal/models"
+	"io"
+	"mime/multipart"
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -11,5 +15,56 @@ type TicketService interface {
 	ListByUser(userID uuid.UUID) ([]models.Ticket, error)
 	ListAll() ([]models.Ticket, error) // New: For agents
 	Update(id uuid.UUID, req *models.UpdateTicketRequest, userID uuid.UUID, role string) (*models.Ticket, error)
+	ApplyReview(id uuid.UUID, req *models.UpdateTicketRequest, agentID uuid.UUID) (*models.Ticket, error) // Update without recording a correction
 	CustomerUpdate(id uuid.UUID, req *models.CustomerUpdateTicketRequest, userID uuid.UUID) (*models.Ticket, error)
+	ListSimilar(id uuid.UUID) ([]models.SimilarTicket, error) // Agents only
+	ConfirmResolution(id, userID uuid.UUID) (*models.Ticket, error)
+	Reopen(id uuid.UUID) error                                   // After a customer reply to an auto-resolved ticket
+	CloseStale(after time.Duration) (int, error)                 // Auto-resolved tickets without a reply
+	GetSummary(id uuid.UUID) (*models.TicketSummary, error)      // Agents only; nil when the AI hasn't summarized the ticket
+	CommentAdded(ticket *models.Ticket, comment *models.Comment) // Lets the AI service refresh the summary
+}
+
+type AttachmentService interface {
+	Upload(ticketID, userID uuid.UUID, role string, file *multipart.FileHeader) (*models.Attachment, error)
+	List(ticketID, userID uuid.UUID, role string) ([]models.Attachment, error)
+	SignedURL(ticketID, attachmentID, userID uuid.UUID, role string) (*models.AttachmentURLResponse, error)
+	Open(attachmentID uuid.UUID, expires, sig string) (*models.Attachment, io.ReadCloser, error) // Verifies the signed link
+	MaxUploadBytes() int64
+}
+
+type CommentService interface {
+	Add(ticketID, userID uuid.UUID, role string, req *models.CreateCommentRequest) (*models.Comment, error)
+	List(ticketID, userID uuid.UUID, role string) ([]models.Comment, error)
+}
+
+type TaxonomyService interface {
+	ListCategories(includeInactive bool) ([]models.Category, error)
+	CreateCategory(req *models.TaxonomyEntryRequest) (*models.Category, error)
+	UpdateCategory(id uuid.UUID, req *models.UpdateTaxonomyEntryRequest) (*models.Category, error)
+	ListPriorities(includeInactive bool) ([]models.Priority, error)
+	CreatePriority(req *models.TaxonomyEntryRequest) (*models.Priority, error)
+	UpdatePriority(id uuid.UUID, req *models.UpdateTaxonomyEntryRequest) (*models.Priority, error)
+}
+
+type ClassificationService interface {
+	ListByTicket(ticketID uuid.UUID) ([]models.AIClassification, error)
+	ListPending() ([]models.AIClassification, error)
+	Review(ticketID, runID, agentID uuid.UUID, req *models.ReviewClassificationRequest) (*models.AIClassification, error)
+	Report(from, to time.Time, interval string) (*models.ClassificationReport, error)
+}
+
+type PromptService interface {
+	List(name string) ([]models.PromptTemplate, error)
+	Create(req *models.CreatePromptRequest, adminID uuid.UUID) (*models.PromptTemplate, error)
+	Activate(id uuid.UUID) (*models.PromptTemplate, error)
+}
+
+type ArticleService interface {
+	Create(req *models.CreateArticleRequest, authorID uuid.UUID) (*models.Article, error)
+	Get(id uuid.UUID, role string) (*models.Article, error)
+	List(role string) ([]models.Article, error)          // Customers see published articles only
+	Search(query, role string) ([]models.Article, error) // Best match first
+	Update(id uuid.UUID, req *models.UpdateArticleRequest) (*models.Article, error)
+	Delete(id uuid.UUID) error
 }
@@ -2,30 +2,27 @@ package invalidator
 
 import (
 	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
 	"ai-ticketing-backend/internal/pkg/redis"
 	"context"
 	"encoding/json"
 	"log"
-	"os"
 
+	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 )
 
-func StartInvalidator(cache *redis.Client) {
-	kafkaBroker := os.Getenv("KAFKA_BROKER")
-	if kafkaBroker == "" {
-		kafkaBroker = "kafka:9092"
-	}
+func StartInvalidator(cache *redis.Client, cfg config.KafkaConfig) {
 	r := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:  []string{kafkaBroker},
-		Topic:    "ticket-events",
+		Brokers:  cfg.Brokers,
+		Topic:    cfg.Topic,
 		GroupID:  "cache-invalidator-group",
 		MinBytes: 10e3,
 		MaxBytes: 10e6,
 	})
 	defer r.Close()
 
-	log.Println("Cache Invalidator listening on ticket-events...")
+	log.Println("Cache Invalidator listening on", cfg.Topic)
 	ctx := context.Background()
 
 	for {
@@ -35,17 +32,24 @@ func StartInvalidator(cache *redis.Client) {
 			continue
 		}
 		// Handle any event (created/updated) — invalidate user tickets
-		var event models.TicketCreatedEvent
+		var event struct {
+			TicketID uuid.UUID `json:"ticket_id"`
+			UserID   uuid.UUID `json:"user_id"`
+		}
 		if err := json.Unmarshal(msg.Value, &event); err != nil {
-			var updatedEvent models.TicketUpdatedEvent
-			if json.Unmarshal(msg.Value, &updatedEvent) == nil {
-				log.Printf("Invalidated caches for updated ticket %s (user %s)", updatedEvent.TicketID, updatedEvent.UserID)
-				cache.CacheDel(ctx, "ticket:"+updatedEvent.TicketID.String())
-				cache.CacheDel(ctx, "user_tickets:"+updatedEvent.UserID.String())
-			}
+			log.Printf("Failed to unmarshal event: %v", err)
 			continue
 		}
-		log.Printf("Invalidated user cache for created ticket %s (user %s)", event.TicketID, event.UserID)
-		cache.CacheDel(ctx, "user_tickets:"+event.UserID.String())
+		switch models.EventType(msg.Value) {
+		case models.EventTicketCreated:
+			log.Printf("Invalidated user cache for created ticket %s (user %s)", event.TicketID, event.UserID)
+			cache.CacheDel(ctx, "user_tickets:"+event.UserID.String())
+		case models.EventTicketUpdated, models.EventTicketContentUpdated, models.EventTicketEscalated, models.EventTicketAssigned:
+			// Also covers changes made outside this service, e.g. by the AI service
+			log.Printf("Invalidated caches for updated ticket %s (user %s)", event.TicketID, event.UserID)
+			cache.CacheDel(ctx, "ticket:"+event.TicketID.String())
+			cache.CacheDel(ctx, "user_tickets:"+event.UserID.String())
+			cache.CacheDel(ctx, "tickets:all")
+		}
 	}
 }
@@ -3,7 +3,6 @@ package middleware
 import (
 	"ai-ticketing-backend/internal/models"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -11,7 +10,7 @@ import (
 )
 
 // AuthMiddleware validates JWT and sets user_id/role in context (no DB lookup)
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -21,13 +20,6 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenStr := strings.Replace(authHeader, "Bearer ", "", 1)
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "JWT secret not configured"})
-			c.Abort()
-			return
-		}
-
 		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 			return []byte(secret), nil
 		})
@@ -54,6 +46,18 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// QueryTokenMiddleware accepts the JWT as ?access_token=, for clients such
+// as EventSource that can't set headers. Use it before AuthMiddleware, and
+// log requests with Logger so the token is masked.
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("access_token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
+
 // New: AgentAuthMiddleware checks for 'agent' role
 func AgentAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -66,3 +70,16 @@ func AgentAuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AdminAuthMiddleware checks for 'admin' role (taxonomy management)
+func AdminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists || role.(string) != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Admin role required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
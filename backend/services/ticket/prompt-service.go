@@ -0,0 +1,58 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/prompt"
+	"ai-ticketing-backend/services/ticket/repository"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type promptService struct {
+	repo repository.PromptRepository
+}
+
+func NewPromptService(repo repository.PromptRepository) PromptService {
+	return &promptService{repo: repo}
+}
+
+func (s *promptService) List(name string) ([]models.PromptTemplate, error) {
+	return s.repo.List(name)
+}
+
+// Create stores body as the next version of the prompt for its tenant, as a candidate
+func (s *promptService) Create(req *models.CreatePromptRequest, adminID uuid.UUID) (*models.PromptTemplate, error) {
+	if err := prompt.Validate(req.Name, req.Body); err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	t := &models.PromptTemplate{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Tenant:    req.Tenant,
+		Body:      req.Body,
+		Status:    models.PromptCandidate,
+		Notes:     req.Notes,
+		CreatedBy: &adminID,
+	}
+	// Stored versions are numbered above the embedded template's, so their
+	// labels can't collide
+	t.Version = prompt.Versions[req.Name]
+	if err := s.repo.Create(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Activate makes a version live for its tenant. Activating an older version
+// is how a bad prompt is rolled back.
+func (s *promptService) Activate(id uuid.UUID) (*models.PromptTemplate, error) {
+	t, err := s.repo.Find(id)
+	if err != nil {
+		return nil, fmt.Errorf("prompt not found")
+	}
+	if err := s.repo.Activate(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,155 @@
+package realtime
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// Start consumes ticket-events and publishes an update for each. Every
+// instance needs every event, so it reads each partition directly from its
+// end rather than joining a consumer group, which would leave a group
+// behind on the broker for every instance that ever ran. Clients resume
+// from the gateway's history, not from Kafka offsets.
+func (g *Gateway) Start(cfg config.KafkaConfig) {
+	partitions := readPartitions(cfg)
+	var wg sync.WaitGroup
+	for _, p := range partitions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.consumePartition(cfg, p.ID)
+		}()
+	}
+	log.Printf("Realtime gateway listening on %s (%d partitions)", cfg.Topic, len(partitions))
+	wg.Wait()
+}
+
+// readPartitions waits until a broker lists the topic's partitions.
+// Partitions added later are picked up on the next restart.
+func readPartitions(cfg config.KafkaConfig) []kafka.Partition {
+	backoff := time.Second
+	for {
+		for _, broker := range cfg.Brokers {
+			conn, err := kafka.Dial("tcp", broker)
+			if err != nil {
+				log.Printf("Failed to reach broker %s: %v", broker, err)
+				continue
+			}
+			partitions, err := conn.ReadPartitions(cfg.Topic)
+			conn.Close()
+			if err == nil && len(partitions) > 0 {
+				return partitions
+			}
+			if err == nil {
+				err = errors.New("topic not created yet")
+			}
+			log.Printf("Failed to read partitions of %s from %s: %v", cfg.Topic, broker, err)
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (g *Gateway) consumePartition(cfg config.KafkaConfig, partition int) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   cfg.Brokers,
+		Topic:     cfg.Topic,
+		Partition: partition,
+		MinBytes:  1,
+		MaxBytes:  10e6,
+		MaxWait:   500 * time.Millisecond, // Updates should reach clients quickly
+	})
+	defer reader.Close()
+	// Only events from now on; StartOffset applies to consumer groups only
+	if err := reader.SetOffset(kafka.LastOffset); err != nil {
+		log.Printf("Failed to start partition %d at its end: %v", partition, err)
+	}
+
+	ctx := context.Background()
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			log.Printf("Error reading message: %v", err)
+			time.Sleep(time.Second) // backoff
+			continue
+		}
+		u, err := g.toUpdate(msg)
+		if err != nil {
+			log.Printf("Skipping realtime update at offset %d: %v", msg.Offset, err)
+			continue
+		}
+		if u != nil {
+			g.publish(u)
+		}
+	}
+}
+
+// toUpdate turns an event into an update with the ticket's current state;
+// nil for events clients don't need
+func (g *Gateway) toUpdate(msg kafka.Message) (*update, error) {
+	eventType := models.EventType(msg.Value)
+	if eventType == "" {
+		return nil, nil
+	}
+	var event struct {
+		TicketID  uuid.UUID  `json:"ticket_id"`
+		CommentID *uuid.UUID `json:"comment_id"`
+		Internal  bool       `json:"internal"`
+	}
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	eventID := models.EventID(msg.Value, msg.Topic, msg.Partition, msg.Offset)
+	ticket, err := g.tickets.FindByID(event.TicketID)
+	if err != nil {
+		return nil, fmt.Errorf("ticket %s not found: %w", event.TicketID, err)
+	}
+	return &update{
+		Event: Event{ID: eventID, Name: eventType, Data: models.TicketUpdate{
+			ID:        eventID,
+			Type:      eventType,
+			TicketID:  ticket.ID,
+			Status:    ticket.Status,
+			Category:  ticket.Category,
+			Priority:  ticket.Priority,
+			CommentID: event.CommentID,
+			UpdatedAt: ticket.UpdatedAt,
+		}},
+		userID:   ticket.UserID,
+		agentID:  ticket.AgentID,
+		internal: event.Internal,
+	}, nil
+}
+
+// StartNotifications pushes in-app notifications, which the notification
+// service publishes on Redis, to their user
+func (g *Gateway) StartNotifications(cache *redis.Client) {
+	pubsub := cache.Subscribe(context.Background(), models.NotificationChannel)
+	defer pubsub.Close()
+	log.Println("Realtime gateway listening for notifications")
+
+	// The channel survives reconnects to Redis
+	for msg := range pubsub.Channel() {
+		var n models.Notification
+		if err := json.Unmarshal([]byte(msg.Payload), &n); err != nil {
+			log.Printf("Skipping invalid notification: %v", err)
+			continue
+		}
+		g.publish(&update{
+			Event:    Event{ID: "notification-" + n.ID.String(), Name: "notification", Data: n},
+			userID:   n.UserID,
+			personal: true,
+		})
+	}
+}
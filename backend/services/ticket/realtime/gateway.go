@@ -0,0 +1,150 @@
+// Package realtime pushes ticket-events and in-app notifications to
+// signed-in clients. Every instance reads the whole topic and keeps recent
+// updates in memory, so a client that reconnects to any instance can
+// resume with Last-Event-ID.
+package realtime
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Scopes an agent can subscribe to
+const (
+	ScopeAll   = "all"   // Every ticket
+	ScopeQueue = "queue" // Tickets assigned to the agent, and unassigned ones
+)
+
+// subscriberBuffer is how many updates a client may fall behind before it
+// is disconnected; it then resumes from where it was
+const subscriberBuffer = 64
+
+// Filter is who a stream is for, from their JWT
+type Filter struct {
+	UserID uuid.UUID
+	Role   string
+	Scope  string // Agents only
+}
+
+// Event is one server-sent event
+type Event struct {
+	ID   string      // Sent back as Last-Event-ID
+	Name string      // A ticket-events type, or "notification"
+	Data interface{} // A TicketUpdate or Notification
+}
+
+// update is an Event with what its recipients are decided by
+type update struct {
+	Event
+	userID   uuid.UUID  // Ticket owner, or the notified user
+	agentID  *uuid.UUID // Assigned agent
+	internal bool       // Internal note, for agents only
+	personal bool       // For userID only, whatever their role
+}
+
+// matches applies the ticket rules: customers see their own tickets, agents
+// their queue or all tickets. Notifications go to their user only.
+func (f Filter) matches(u *update) bool {
+	if u.personal {
+		return u.userID == f.UserID
+	}
+	if f.Role != "agent" {
+		return u.userID == f.UserID && !u.internal
+	}
+	if f.Scope == ScopeQueue {
+		return u.agentID == nil || *u.agentID == f.UserID
+	}
+	return true
+}
+
+// Subscription receives the updates matching its filter. Its channel is
+// closed when the client falls too far behind.
+type Subscription struct {
+	filter  Filter
+	updates chan Event
+}
+
+// Updates delivers the subscription's events
+func (s *Subscription) Updates() <-chan Event {
+	return s.updates
+}
+
+// Gateway fans ticket updates out to subscriptions
+type Gateway struct {
+	tickets TicketFinder
+	mu      sync.Mutex
+	history []*update // Oldest first
+	size    int
+	subs    map[*Subscription]struct{}
+}
+
+// TicketFinder loads the ticket an event is about
+type TicketFinder interface {
+	FindByID(id uuid.UUID) (*models.Ticket, error)
+}
+
+// NewGateway keeps the last history updates for resuming clients
+func NewGateway(tickets TicketFinder, history int) *Gateway {
+	return &Gateway{tickets: tickets, size: history, subs: map[*Subscription]struct{}{}}
+}
+
+// Subscribe starts a subscription. With lastEventID it also returns the
+// matching updates since that event; ok is false when the event is no
+// longer in the history, so the client should reload instead.
+func (g *Gateway) Subscribe(filter Filter, lastEventID string) (sub *Subscription, missed []Event, ok bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	sub = &Subscription{filter: filter, updates: make(chan Event, subscriberBuffer)}
+	g.subs[sub] = struct{}{}
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	for i := len(g.history) - 1; i >= 0; i-- {
+		if g.history[i].ID != lastEventID {
+			continue
+		}
+		for _, u := range g.history[i+1:] {
+			if filter.matches(u) {
+				missed = append(missed, u.Event)
+			}
+		}
+		return sub, missed, true
+	}
+	return sub, nil, false
+}
+
+// Unsubscribe ends a subscription
+func (g *Gateway) Unsubscribe(sub *Subscription) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.subs[sub]; ok {
+		delete(g.subs, sub)
+		close(sub.updates)
+	}
+}
+
+// publish records u and sends it to every matching subscription
+func (g *Gateway) publish(u *update) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.size > 0 {
+		g.history = append(g.history, u)
+		if len(g.history) > g.size {
+			g.history = g.history[len(g.history)-g.size:]
+		}
+	}
+	for sub := range g.subs {
+		if !sub.filter.matches(u) {
+			continue
+		}
+		select {
+		case sub.updates <- u.Event:
+		default:
+			// Slow client: drop it rather than hold everyone else up
+			delete(g.subs, sub)
+			close(sub.updates)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package realtime
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFilterMatches(t *testing.T) {
+	customer, agent, otherAgent := uuid.New(), uuid.New(), uuid.New()
+	tests := []struct {
+		name   string
+		filter Filter
+		update update
+		want   bool
+	}{
+		{"customer's own ticket", Filter{UserID: customer, Role: "customer"}, update{userID: customer}, true},
+		{"another customer's ticket", Filter{UserID: customer, Role: "customer"}, update{userID: uuid.New()}, false},
+		{"internal note on own ticket", Filter{UserID: customer, Role: "customer"}, update{userID: customer, internal: true}, false},
+		{"agent sees every ticket", Filter{UserID: agent, Role: "agent"}, update{userID: customer, agentID: &otherAgent}, true},
+		{"agent sees internal notes", Filter{UserID: agent, Role: "agent"}, update{userID: customer, internal: true}, true},
+		{"queue: assigned to the agent", Filter{UserID: agent, Role: "agent", Scope: ScopeQueue}, update{userID: customer, agentID: &agent}, true},
+		{"queue: unassigned", Filter{UserID: agent, Role: "agent", Scope: ScopeQueue}, update{userID: customer}, true},
+		{"queue: assigned to someone else", Filter{UserID: agent, Role: "agent", Scope: ScopeQueue}, update{userID: customer, agentID: &otherAgent}, false},
+		{"notification for the user", Filter{UserID: agent, Role: "agent"}, update{userID: agent, personal: true}, true},
+		{"notification for someone else", Filter{UserID: agent, Role: "agent"}, update{userID: customer, personal: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(&tt.update); got != tt.want {
+				t.Errorf("matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/db"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+type ArticleRepository interface {
+	Create(article *models.Article) error
+	Find(id uuid.UUID) (*models.Article, error)
+	List(publishedOnly bool) ([]models.Article, error) // Newest first
+	Search(query string, publishedOnly bool, limit int) ([]models.Article, error)
+	Update(article *models.Article) error
+	Delete(id uuid.UUID) error
+}
+
+type articleRepository struct {
+	db *db.DB
+}
+
+func NewArticleRepository(db *db.DB) ArticleRepository {
+	return &articleRepository{db: db}
+}
+
+func (r *articleRepository) Create(article *models.Article) error {
+	return r.db.Create(article).Error
+}
+
+func (r *articleRepository) Find(id uuid.UUID) (*models.Article, error) {
+	var article models.Article
+	if err := r.db.Where("id = ?", id).First(&article).Error; err != nil {
+		return nil, err
+	}
+	return &article, nil
+}
+
+func (r *articleRepository) List(publishedOnly bool) ([]models.Article, error) {
+	var articles []models.Article
+	q := r.db.Order("updated_at DESC")
+	if publishedOnly {
+		q = q.Where("published = ?", true)
+	}
+	err := q.Find(&articles).Error
+	return articles, err
+}
+
+// Search uses Postgres full-text search; websearch syntax lets customers
+// type "refund OR chargeback" or "-password" naturally
+func (r *articleRepository) Search(query string, publishedOnly bool, limit int) ([]models.Article, error) {
+	const document = "to_tsvector('english', title || ' ' || body)"
+	var articles []models.Article
+	q := r.db.Where(document+" @@ websearch_to_tsquery('english', ?)", query).
+		Order(clause.Expr{SQL: "ts_rank(" + document + ", websearch_to_tsquery('english', ?)) DESC", Vars: []interface{}{query}}).
+		Limit(limit)
+	if publishedOnly {
+		q = q.Where("published = ?", true)
+	}
+	err := q.Find(&articles).Error
+	return articles, err
+}
+
+func (r *articleRepository) Update(article *models.Article) error {
+	return r.db.Save(article).Error
+}
+
+func (r *articleRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&models.Article{}).Error
+}
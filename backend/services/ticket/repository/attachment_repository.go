@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentRepository interface {
+	Create(attachment *models.Attachment) error
+	FindByID(id uuid.UUID) (*models.Attachment, error)
+	ListByTicket(ticketID uuid.UUID) ([]models.Attachment, error)
+}
+
+type attachmentRepository struct {
+	db *db.DB
+}
+
+func NewAttachmentRepository(db *db.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) FindByID(id uuid.UUID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.Where("id = ?", id).First(&attachment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) ListByTicket(ticketID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("ticket_id = ?", ticketID).Order("created_at").Find(&attachments).Error
+	return attachments, err
+}
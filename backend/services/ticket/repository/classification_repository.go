@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/db"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSuperseded = errors.New("a newer classification replaced this one")
+	ErrReviewed   = errors.New("classification was already reviewed")
+)
+
+type ClassificationRepository interface {
+	FindByID(id uuid.UUID) (*models.AIClassification, error)
+	ListByTicket(ticketID uuid.UUID) ([]models.AIClassification, error) // Newest first
+	ListPending() ([]models.AIClassification, error)                    // Review queue, oldest first
+	Latest(ticketID uuid.UUID) (*models.AIClassification, error)
+	ListBetween(from, to time.Time) ([]models.AIClassification, error)
+	Update(run *models.AIClassification) error
+	// ClaimReview marks the run reviewed by agentID if it is still the
+	// ticket's latest run and unreviewed (ErrSuperseded, ErrReviewed)
+	ClaimReview(run *models.AIClassification, agentID uuid.UUID) error
+	ReleaseReview(run *models.AIClassification) error // Undoes ClaimReview when the review couldn't be applied
+	CreateFeedback(feedback []models.ClassificationFeedback) error
+}
+
+type classificationRepository struct {
+	db *db.DB
+}
+
+func NewClassificationRepository(db *db.DB) ClassificationRepository {
+	return &classificationRepository{db: db}
+}
+
+func (r *classificationRepository) FindByID(id uuid.UUID) (*models.AIClassification, error) {
+	var run models.AIClassification
+	if err := r.db.Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *classificationRepository) ListByTicket(ticketID uuid.UUID) ([]models.AIClassification, error) {
+	var runs []models.AIClassification
+	err := r.db.Where("ticket_id = ?", ticketID).Order("created_at DESC").Find(&runs).Error
+	return runs, err
+}
+
+func (r *classificationRepository) ListPending() ([]models.AIClassification, error) {
+	var runs []models.AIClassification
+	err := r.db.Where("status = ?", models.ClassificationPending).Order("created_at").Find(&runs).Error
+	return runs, err
+}
+
+func (r *classificationRepository) Update(run *models.AIClassification) error {
+	return r.db.Save(run).Error
+}
+
+func (r *classificationRepository) ClaimReview(run *models.AIClassification, agentID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The lock makes concurrent reviews of the run wait, and the AI
+		// service can't supersede it in between
+		var latest models.AIClassification
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("ticket_id = ?", run.TicketID).Order("created_at DESC").First(&latest).Error; err != nil {
+			return err
+		}
+		if latest.ID != run.ID || latest.Status == models.ClassificationSuperseded {
+			return ErrSuperseded
+		}
+		if latest.ReviewedAt != nil {
+			return ErrReviewed
+		}
+		now := time.Now()
+		run.ReviewedBy, run.ReviewedAt = &agentID, &now
+		return tx.Model(&latest).Updates(map[string]interface{}{"reviewed_by": agentID, "reviewed_at": now}).Error
+	})
+}
+
+func (r *classificationRepository) ReleaseReview(run *models.AIClassification) error {
+	run.ReviewedBy, run.ReviewedAt = nil, nil
+	return r.db.Model(run).Updates(map[string]interface{}{"reviewed_by": nil, "reviewed_at": nil}).Error
+}
+
+func (r *classificationRepository) Latest(ticketID uuid.UUID) (*models.AIClassification, error) {
+	var run models.AIClassification
+	if err := r.db.Where("ticket_id = ?", ticketID).Order("created_at DESC").First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *classificationRepository) ListBetween(from, to time.Time) ([]models.AIClassification, error) {
+	var runs []models.AIClassification
+	err := r.db.Where("created_at >= ? AND created_at < ?", from, to).Order("created_at").Find(&runs).Error
+	return runs, err
+}
+
+func (r *classificationRepository) CreateFeedback(feedback []models.ClassificationFeedback) error {
+	if len(feedback) == 0 {
+		return nil
+	}
+	return r.db.Create(&feedback).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	ListByTicket(ticketID uuid.UUID, publicOnly bool) ([]models.Comment, error)
+	FindDraft(id uuid.UUID) (*models.ReplyDraft, error)
+	UpdateDraft(draft *models.ReplyDraft) error
+}
+
+type commentRepository struct {
+	db *db.DB
+}
+
+func NewCommentRepository(db *db.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) Create(comment *models.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *commentRepository) ListByTicket(ticketID uuid.UUID, publicOnly bool) ([]models.Comment, error) {
+	var comments []models.Comment
+	q := r.db.Where("ticket_id = ?", ticketID)
+	if publicOnly {
+		q = q.Where("public = ?", true)
+	}
+	err := q.Order("created_at").Find(&comments).Error
+	return comments, err
+}
+
+func (r *commentRepository) FindDraft(id uuid.UUID) (*models.ReplyDraft, error) {
+	var draft models.ReplyDraft
+	if err := r.db.First(&draft, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+func (r *commentRepository) UpdateDraft(draft *models.ReplyDraft) error {
+	return r.db.Save(draft).Error
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/db"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PromptRepository interface {
+	List(name string) ([]models.PromptTemplate, error) // All versions, newest first; empty name lists every prompt
+	Find(id uuid.UUID) (*models.PromptTemplate, error)
+	Create(t *models.PromptTemplate) error   // Numbers t after the latest version for its name and tenant, and after t.Version
+	Activate(t *models.PromptTemplate) error // Retires the previously active version for the same name and tenant
+}
+
+type promptRepository struct {
+	db *db.DB
+}
+
+func NewPromptRepository(db *db.DB) PromptRepository {
+	return &promptRepository{db: db}
+}
+
+func (r *promptRepository) List(name string) ([]models.PromptTemplate, error) {
+	var templates []models.PromptTemplate
+	q := r.db.Order("name, version DESC")
+	if name != "" {
+		q = q.Where("name = ?", name)
+	}
+	err := q.Find(&templates).Error
+	return templates, err
+}
+
+func (r *promptRepository) Find(id uuid.UUID) (*models.PromptTemplate, error) {
+	var t models.PromptTemplate
+	if err := r.db.Where("id = ?", id).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *promptRepository) Create(t *models.PromptTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Concurrent creates for the same prompt wait here instead of taking the same number
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", t.Name+"@"+t.Tenant).Error; err != nil {
+			return err
+		}
+		var latest int
+		err := tx.Model(&models.PromptTemplate{}).Where("name = ? AND tenant = ?", t.Name, t.Tenant).
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		t.Version = max(latest, t.Version) + 1
+		return tx.Create(t).Error
+	})
+}
+
+func (r *promptRepository) Activate(t *models.PromptTemplate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.PromptTemplate{}).
+			Where("name = ? AND tenant = ? AND status = ? AND id <> ?", t.Name, t.Tenant, models.PromptActive, t.ID).
+			Update("status", models.PromptRetired).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		t.Status = models.PromptActive
+		t.ActivatedAt = &now
+		return tx.Save(t).Error
+	})
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/db"
+
+	"github.com/google/uuid"
+)
+
+type TaxonomyRepository interface {
+	ListCategories(activeOnly bool) ([]models.Category, error)
+	FindCategory(id uuid.UUID) (*models.Category, error)
+	FindCategoryByName(name string) (*models.Category, error)    // Case-insensitive, active only
+	FindAnyCategoryByName(name string) (*models.Category, error) // Case-insensitive, inactive included
+	SaveCategory(category *models.Category) error
+	ListPriorities(activeOnly bool) ([]models.Priority, error)
+	FindPriority(id uuid.UUID) (*models.Priority, error)
+	FindPriorityByName(name string) (*models.Priority, error)    // Case-insensitive, active only
+	FindAnyPriorityByName(name string) (*models.Priority, error) // Case-insensitive, inactive included
+	SavePriority(priority *models.Priority) error
+}
+
+type taxonomyRepository struct {
+	db *db.DB
+}
+
+func NewTaxonomyRepository(db *db.DB) TaxonomyRepository {
+	return &taxonomyRepository{db: db}
+}
+
+func (r *taxonomyRepository) ListCategories(activeOnly bool) ([]models.Category, error) {
+	var categories []models.Category
+	q := r.db.Order("name")
+	if activeOnly {
+		q = q.Where("active = ?", true)
+	}
+	err := q.Find(&categories).Error
+	return categories, err
+}
+
+func (r *taxonomyRepository) FindCategory(id uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("id = ?", id).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *taxonomyRepository) FindCategoryByName(name string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("LOWER(name) = LOWER(?) AND active = ?", name, true).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *taxonomyRepository) FindAnyCategoryByName(name string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *taxonomyRepository) SaveCategory(category *models.Category) error {
+	return r.db.Save(category).Error
+}
+
+func (r *taxonomyRepository) ListPriorities(activeOnly bool) ([]models.Priority, error) {
+	var priorities []models.Priority
+	q := r.db.Order("rank")
+	if activeOnly {
+		q = q.Where("active = ?", true)
+	}
+	err := q.Find(&priorities).Error
+	return priorities, err
+}
+
+func (r *taxonomyRepository) FindPriority(id uuid.UUID) (*models.Priority, error) {
+	var priority models.Priority
+	if err := r.db.Where("id = ?", id).First(&priority).Error; err != nil {
+		return nil, err
+	}
+	return &priority, nil
+}
+
+func (r *taxonomyRepository) FindPriorityByName(name string) (*models.Priority, error) {
+	var priority models.Priority
+	if err := r.db.Where("LOWER(name) = LOWER(?) AND active = ?", name, true).First(&priority).Error; err != nil {
+		return nil, err
+	}
+	return &priority, nil
+}
+
+func (r *taxonomyRepository) FindAnyPriorityByName(name string) (*models.Priority, error) {
+	var priority models.Priority
+	if err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&priority).Error; err != nil {
+		return nil, err
+	}
+	return &priority, nil
+}
+
+func (r *taxonomyRepository) SavePriority(priority *models.Priority) error {
+	return r.db.Save(priority).Error
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"ai-ticketing-backend/internal/models"
 	"ai-ticketing-backend/internal/pkg/db"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,6 +15,12 @@ type TicketRepository interface {
 	ListByUser(userID uuid.UUID) ([]models.Ticket, error) // User's tickets only
 	ListAll() ([]models.Ticket, error) // New: For agents
 	Update(ticket *models.Ticket) error
+	ListSimilar(id uuid.UUID) ([]models.SimilarTicket, error) // Both directions, best score first
+	// CloseAutoResolvedBefore closes auto-resolved tickets that have been
+	// waiting for the customer since before cutoff and returns them. A ticket
+	// is closed, and returned, by one caller only.
+	CloseAutoResolvedBefore(cutoff time.Time) ([]models.Ticket, error)
+	FindSummary(id uuid.UUID) (*models.TicketSummary, error) // nil if the ticket has no summary yet
 }
 
 type ticketRepository struct {
@@ -29,7 +37,7 @@ func (r *ticketRepository) Create(ticket *models.Ticket) error {
 
 func (r *ticketRepository) FindByID(id uuid.UUID) (*models.Ticket, error) {
 	var ticket models.Ticket
-	err := r.db.Preload("User").Where("id = ?", id).First(&ticket).Error // Preload user
+	err := r.db.Preload("User").Preload("Attachments").Where("id = ?", id).First(&ticket).Error // Preload user
 	if err != nil {
 		return nil, err
 	}
@@ -52,3 +60,63 @@ func (r *ticketRepository) ListAll() ([]models.Ticket, error) {
 func (r *ticketRepository) Update(ticket *models.Ticket) error {
 	return r.db.Save(ticket).Error // Updates timestamps auto
 }
+
+func (r *ticketRepository) ListSimilar(id uuid.UUID) ([]models.SimilarTicket, error) {
+	var links []models.TicketSimilarity
+	if err := r.db.Where("ticket_id = ? OR similar_ticket_id = ?", id, id).Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	// A pair can be linked from either side; keep the best score per ticket
+	best := map[uuid.UUID]models.TicketSimilarity{}
+	for _, l := range links {
+		other := l.SimilarTicketID
+		if other == id {
+			other = l.TicketID
+		}
+		if b, ok := best[other]; !ok || l.Score > b.Score {
+			best[other] = l
+		}
+	}
+	if len(best) == 0 {
+		return []models.SimilarTicket{}, nil
+	}
+	ids := make([]uuid.UUID, 0, len(best))
+	for other := range best {
+		ids = append(ids, other)
+	}
+	var tickets []models.Ticket
+	if err := r.db.Where("id IN ?", ids).Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+
+	similar := make([]models.SimilarTicket, 0, len(tickets))
+	for _, t := range tickets {
+		l := best[t.ID]
+		similar = append(similar, models.SimilarTicket{
+			TicketID: t.ID, Title: t.Title, Status: t.Status, Category: t.Category, CreatedAt: t.CreatedAt,
+			Score: l.Score, Kind: l.Kind,
+		})
+	}
+	sort.Slice(similar, func(i, j int) bool { return similar[i].Score > similar[j].Score })
+	return similar, nil
+}
+
+func (r *ticketRepository) CloseAutoResolvedBefore(cutoff time.Time) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	// A replica that loses the race for a row finds it closed and skips it
+	err := r.db.Raw(`UPDATE tickets SET status = ?, pending_customer_since = NULL, updated_at = NOW()
+		WHERE status = ? AND auto_resolved_at IS NOT NULL AND pending_customer_since < ?
+		RETURNING *`,
+		"closed", "pending_customer", cutoff).
+		Scan(&tickets).Error
+	return tickets, err
+}
+
+func (r *ticketRepository) FindSummary(id uuid.UUID) (*models.TicketSummary, error) {
+	var summaries []models.TicketSummary
+	if err := r.db.Where("ticket_id = ?", id).Limit(1).Find(&summaries).Error; err != nil || len(summaries) == 0 {
+		return nil, err
+	}
+	return &summaries[0], nil
+}
@@ -0,0 +1,133 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket/repository"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type taxonomyService struct {
+	repo repository.TaxonomyRepository
+}
+
+func NewTaxonomyService(repo repository.TaxonomyRepository) TaxonomyService {
+	return &taxonomyService{repo: repo}
+}
+
+func (s *taxonomyService) ListCategories(includeInactive bool) ([]models.Category, error) {
+	return s.repo.ListCategories(!includeInactive)
+}
+
+// CreateCategory adds a category. Re-creating a deactivated one reactivates
+// it with the new details, keeping its ID and history.
+func (s *taxonomyService) CreateCategory(req *models.TaxonomyEntryRequest) (*models.Category, error) {
+	category, err := s.repo.FindAnyCategoryByName(req.Name)
+	switch {
+	case err == nil && category.Active:
+		return nil, fmt.Errorf("invalid category: %q already exists", req.Name)
+	case err == nil:
+		category.Description = req.Description
+		category.Examples = req.Examples
+		category.Active = true
+		category.AutoResolve = req.AutoResolve
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		category = &models.Category{
+			ID:          uuid.New(),
+			Name:        req.Name,
+			Description: req.Description,
+			Examples:    req.Examples,
+			Active:      true,
+			AutoResolve: req.AutoResolve,
+		}
+	default:
+		return nil, err
+	}
+	if err := s.repo.SaveCategory(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *taxonomyService) UpdateCategory(id uuid.UUID, req *models.UpdateTaxonomyEntryRequest) (*models.Category, error) {
+	category, err := s.repo.FindCategory(id)
+	if err != nil {
+		return nil, fmt.Errorf("category not found")
+	}
+	if req.Description != nil {
+		category.Description = *req.Description
+	}
+	if req.Examples != nil {
+		category.Examples = *req.Examples
+	}
+	if req.Active != nil {
+		category.Active = *req.Active
+	}
+	if req.AutoResolve != nil {
+		category.AutoResolve = *req.AutoResolve
+	}
+	if err := s.repo.SaveCategory(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *taxonomyService) ListPriorities(includeInactive bool) ([]models.Priority, error) {
+	return s.repo.ListPriorities(!includeInactive)
+}
+
+// CreatePriority adds a priority, or reactivates a deactivated one of the
+// same name like CreateCategory
+func (s *taxonomyService) CreatePriority(req *models.TaxonomyEntryRequest) (*models.Priority, error) {
+	priority, err := s.repo.FindAnyPriorityByName(req.Name)
+	switch {
+	case err == nil && priority.Active:
+		return nil, fmt.Errorf("invalid priority: %q already exists", req.Name)
+	case err == nil:
+		priority.Description = req.Description
+		priority.Examples = req.Examples
+		priority.Rank = req.Rank
+		priority.Active = true
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		priority = &models.Priority{
+			ID:          uuid.New(),
+			Name:        req.Name,
+			Description: req.Description,
+			Examples:    req.Examples,
+			Rank:        req.Rank,
+			Active:      true,
+		}
+	default:
+		return nil, err
+	}
+	if err := s.repo.SavePriority(priority); err != nil {
+		return nil, err
+	}
+	return priority, nil
+}
+
+func (s *taxonomyService) UpdatePriority(id uuid.UUID, req *models.UpdateTaxonomyEntryRequest) (*models.Priority, error) {
+	priority, err := s.repo.FindPriority(id)
+	if err != nil {
+		return nil, fmt.Errorf("priority not found")
+	}
+	if req.Description != nil {
+		priority.Description = *req.Description
+	}
+	if req.Examples != nil {
+		priority.Examples = *req.Examples
+	}
+	if req.Rank != nil {
+		priority.Rank = *req.Rank
+	}
+	if req.Active != nil {
+		priority.Active = *req.Active
+	}
+	if err := s.repo.SavePriority(priority); err != nil {
+		return nil, err
+	}
+	return priority, nil
+}
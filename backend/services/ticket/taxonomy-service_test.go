@@ -0,0 +1,129 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/services/ticket/repository"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeTaxonomy keeps entries by lower-cased name, like the case-insensitive
+// lookups of the real repository
+type fakeTaxonomy struct {
+	repository.TaxonomyRepository
+	categories map[string]*models.Category
+	priorities map[string]*models.Priority
+}
+
+func (f *fakeTaxonomy) FindAnyCategoryByName(name string) (*models.Category, error) {
+	if c, ok := f.categories[strings.ToLower(name)]; ok {
+		copied := *c
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeTaxonomy) SaveCategory(category *models.Category) error {
+	f.categories[strings.ToLower(category.Name)] = category
+	return nil
+}
+
+func (f *fakeTaxonomy) FindAnyPriorityByName(name string) (*models.Priority, error) {
+	if p, ok := f.priorities[strings.ToLower(name)]; ok {
+		copied := *p
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeTaxonomy) SavePriority(priority *models.Priority) error {
+	f.priorities[strings.ToLower(priority.Name)] = priority
+	return nil
+}
+
+func TestCreateCategory(t *testing.T) {
+	activeID, inactiveID := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		req     models.TaxonomyEntryRequest
+		wantID  uuid.UUID // Nil for a new category
+		wantErr string
+	}{
+		{"new", models.TaxonomyEntryRequest{Name: "Shipping", Description: "Deliveries"}, uuid.Nil, ""},
+		{"active duplicate", models.TaxonomyEntryRequest{Name: "Billing"}, uuid.Nil, `invalid category: "Billing" already exists`},
+		{"active duplicate in another case", models.TaxonomyEntryRequest{Name: "billing"}, uuid.Nil, "already exists"},
+		{"deactivated one reactivated", models.TaxonomyEntryRequest{Name: "Legacy", Description: "Old plans", AutoResolve: true}, inactiveID, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTaxonomy{categories: map[string]*models.Category{
+				"billing": {ID: activeID, Name: "Billing", Active: true},
+				"legacy":  {ID: inactiveID, Name: "Legacy", Description: "Retired"},
+			}}
+			svc := NewTaxonomyService(repo)
+			got, err := svc.CreateCategory(&tt.req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreateCategory error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantID != uuid.Nil && got.ID != tt.wantID {
+				t.Errorf("ID = %s, want the deactivated category's %s", got.ID, tt.wantID)
+			}
+			if tt.wantID == uuid.Nil && (got.ID == uuid.Nil || got.ID == activeID || got.ID == inactiveID) {
+				t.Errorf("ID = %s, want a fresh one", got.ID)
+			}
+			if !got.Active || got.Description != tt.req.Description || got.AutoResolve != tt.req.AutoResolve {
+				t.Errorf("CreateCategory = %+v, want an active category with the request's details", got)
+			}
+			if saved := repo.categories[strings.ToLower(tt.req.Name)]; saved != got {
+				t.Errorf("saved %+v, want the returned category", saved)
+			}
+		})
+	}
+}
+
+func TestCreatePriority(t *testing.T) {
+	inactiveID := uuid.New()
+	tests := []struct {
+		name    string
+		req     models.TaxonomyEntryRequest
+		wantID  uuid.UUID // Nil for a new priority
+		wantErr string
+	}{
+		{"new", models.TaxonomyEntryRequest{Name: "critical", Rank: 4}, uuid.Nil, ""},
+		{"active duplicate", models.TaxonomyEntryRequest{Name: "HIGH", Rank: 3}, uuid.Nil, `invalid priority: "HIGH" already exists`},
+		{"deactivated one reactivated with a new rank", models.TaxonomyEntryRequest{Name: "trivial", Rank: 0}, inactiveID, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTaxonomy{priorities: map[string]*models.Priority{
+				"high":    {ID: uuid.New(), Name: "high", Rank: 3, Active: true},
+				"trivial": {ID: inactiveID, Name: "trivial", Rank: -1},
+			}}
+			got, err := NewTaxonomyService(repo).CreatePriority(&tt.req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreatePriority error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantID != uuid.Nil && got.ID != tt.wantID {
+				t.Errorf("ID = %s, want the deactivated priority's %s", got.ID, tt.wantID)
+			}
+			if !got.Active || got.Rank != tt.req.Rank {
+				t.Errorf("CreatePriority = %+v, want it active with rank %d", got, tt.req.Rank)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package ticket
 
 import (
 	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
 	"ai-ticketing-backend/internal/pkg/metrics"
 	"ai-ticketing-backend/internal/pkg/redis"
 	"ai-ticketing-backend/services/ticket/repository"
@@ -9,7 +10,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,25 +17,21 @@ import (
 )
 
 type ticketService struct {
-	repo     repository.TicketRepository
-	producer *kafka.Writer
-	cache    *redis.Client
+	repo            repository.TicketRepository
+	taxonomy        repository.TaxonomyRepository
+	classifications repository.ClassificationRepository
+	producer        *kafka.Writer
+	cache           *redis.Client
 }
 
-func NewTicketService(repo repository.TicketRepository) TicketService {
-
-	broker := os.Getenv("KAFKA_BROKER")
-	if broker == "" {
-		broker = "kafka:9092" // fallback for local dev
-	}
-
+func NewTicketService(repo repository.TicketRepository, taxonomy repository.TaxonomyRepository, classifications repository.ClassificationRepository, cfg *config.Config) TicketService {
 	writer := &kafka.Writer{
-		Addr:     kafka.TCP(broker),
-		Topic:    "ticket-events",
-		Balancer: &kafka.LeastBytes{},
+		Addr:     kafka.TCP(cfg.Kafka.Brokers...),
+		Topic:    cfg.Kafka.Topic,
+		Balancer: &kafka.Hash{}, // Keyed by ticket ID, so each ticket's events stay in order
 	}
-	cache := redis.New()
-	return &ticketService{repo: repo, producer: writer, cache: cache}
+	cache := redis.New(cfg.Redis.Addr)
+	return &ticketService{repo: repo, taxonomy: taxonomy, classifications: classifications, producer: writer, cache: cache}
 }
 
 func (s *ticketService) Create(req *models.CreateTicketRequest, userID uuid.UUID) (*models.Ticket, error) {
@@ -51,6 +47,8 @@ func (s *ticketService) Create(req *models.CreateTicketRequest, userID uuid.UUID
 
 	// Publish event with segmentio
 	event := models.TicketCreatedEvent{
+		Type:        models.EventTicketCreated,
+		EventID:     uuid.New().String(),
 		TicketID:    ticket.ID,
 		UserID:      userID,
 		Title:       req.Title,
@@ -64,7 +62,7 @@ func (s *ticketService) Create(req *models.CreateTicketRequest, userID uuid.UUID
 	}
 
 	err = s.producer.WriteMessages(context.Background(),
-		kafka.Message{Value: eventBytes},
+		kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes},
 	)
 	if err != nil {
 		log.Printf("failed to produce event: %v", err)
@@ -154,6 +152,18 @@ func (s *ticketService) ListAll() ([]models.Ticket, error) {
 }
 
 func (s *ticketService) Update(id uuid.UUID, req *models.UpdateTicketRequest, userID uuid.UUID, role string) (*models.Ticket, error) {
+	return s.update(id, req, userID, role, true)
+}
+
+// ApplyReview updates the ticket for a classification review, which records
+// its own feedback against the reviewed run
+func (s *ticketService) ApplyReview(id uuid.UUID, req *models.UpdateTicketRequest, agentID uuid.UUID) (*models.Ticket, error) {
+	return s.update(id, req, agentID, "agent", false)
+}
+
+// update applies an agent's changes; with correction, changed categories and
+// priorities are booked against the latest AI run
+func (s *ticketService) update(id uuid.UUID, req *models.UpdateTicketRequest, userID uuid.UUID, role string, correction bool) (*models.Ticket, error) {
 	ticket, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
@@ -164,6 +174,7 @@ func (s *ticketService) Update(id uuid.UUID, req *models.UpdateTicketRequest, us
 	}
 
 	oldStatus := ticket.Status
+	oldCategory, oldPriority := ticket.Category, ticket.Priority
 	if req.Title != nil {
 		ticket.Title = *req.Title
 	}
@@ -172,45 +183,214 @@ func (s *ticketService) Update(id uuid.UUID, req *models.UpdateTicketRequest, us
 	}
 	if req.Status != nil {
 		ticket.Status = *req.Status
+		trackPending(ticket, oldStatus)
+		// The agent has taken over, so a customer reply no longer reopens
+		// the ticket as a rejected auto-resolution
+		if ticket.Status != oldStatus {
+			ticket.AutoResolvedAt = nil
+		}
+	}
+	if req.Category != nil {
+		category, err := s.taxonomy.FindCategoryByName(*req.Category)
+		if err != nil {
+			return nil, fmt.Errorf("invalid category %q", *req.Category)
+		}
+		ticket.Category = category.Name // Canonical casing
+	}
+	if req.Priority != nil {
+		priority, err := s.taxonomy.FindPriorityByName(*req.Priority)
+		if err != nil {
+			return nil, fmt.Errorf("invalid priority %q", *req.Priority)
+		}
+		ticket.Priority = priority.Name
+	}
+	if req.Suggestion != nil {
+		ticket.Suggestion = *req.Suggestion
 	}
 
+	assigned := false
 	if ticket.AgentID == nil {
 		ticket.AgentID = &userID
+		assigned = true
 	}
 
 	if err := s.repo.Update(ticket); err != nil {
 		return nil, err
 	}
+	s.invalidate(ticket)
+
+	if correction && (ticket.Category != oldCategory || ticket.Priority != oldPriority) {
+		s.recordCorrection(ticket, userID)
+	}
+	s.publishStatusChange(ticket, oldStatus)
+	if assigned {
+		s.publishAssignment(ticket, userID)
+	}
+
+	return ticket, nil
+}
 
+// ConfirmResolution closes an auto-resolved ticket at the customer's request
+func (s *ticketService) ConfirmResolution(id, userID uuid.UUID) (*models.Ticket, error) {
+	ticket, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.UserID != userID {
+		return nil, fmt.Errorf("unauthorized: not your ticket")
+	}
+	if ticket.Status != "pending_customer" {
+		return nil, fmt.Errorf("invalid status: ticket is %s, not awaiting confirmation", ticket.Status)
+	}
+	return ticket, s.setStatus(ticket, "closed")
+}
+
+// Reopen puts an auto-resolved ticket back in the queue when the customer
+// replies; tickets in any other state are left alone
+func (s *ticketService) Reopen(id uuid.UUID) error {
+	ticket, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if ticket.AutoResolvedAt == nil || (ticket.Status != "pending_customer" && ticket.Status != "closed") {
+		return nil
+	}
+	return s.setStatus(ticket, "open")
+}
+
+// CloseStale closes auto-resolved tickets the customer hasn't answered
+// within after of them starting to wait, returning how many were closed.
+// Every replica runs it; each ticket is closed and announced once.
+func (s *ticketService) CloseStale(after time.Duration) (int, error) {
+	tickets, err := s.repo.CloseAutoResolvedBefore(time.Now().Add(-after))
+	if err != nil {
+		return 0, err
+	}
+	for i := range tickets {
+		s.invalidate(&tickets[i])
+		s.publishStatusChange(&tickets[i], "pending_customer")
+	}
+	return len(tickets), nil
+}
+
+func (s *ticketService) setStatus(ticket *models.Ticket, status string) error {
+	oldStatus := ticket.Status
+	ticket.Status = status
+	trackPending(ticket, oldStatus)
+	if err := s.repo.Update(ticket); err != nil {
+		return err
+	}
+	s.invalidate(ticket)
+	s.publishStatusChange(ticket, oldStatus)
+	return nil
+}
+
+// trackPending records when the ticket started waiting for the customer,
+// which is what auto-close counts from
+func trackPending(ticket *models.Ticket, oldStatus string) {
+	if ticket.Status == oldStatus {
+		return
+	}
+	if ticket.Status == "pending_customer" {
+		now := time.Now()
+		ticket.PendingCustomerSince = &now
+	} else {
+		ticket.PendingCustomerSince = nil
+	}
+}
+
+func (s *ticketService) invalidate(ticket *models.Ticket) {
 	ctx := context.Background()
-	s.cache.CacheDel(ctx, "ticket:"+id.String())
+	s.cache.CacheDel(ctx, "ticket:"+ticket.ID.String())
 	s.cache.CacheDel(ctx, "user_tickets:"+ticket.UserID.String())
 	s.cache.CacheDel(ctx, "tickets:all")
+}
 
-	if oldStatus != ticket.Status {
-		event := models.TicketUpdatedEvent{
-			TicketID:  id,
-			UserID:    ticket.UserID,
-			OldStatus: oldStatus,
-			NewStatus: ticket.Status,
-			UpdatedAt: time.Now().Format(time.RFC3339),
-		}
-		eventBytes, err := json.Marshal(event)
-		if err != nil {
-			log.Printf("failed to marshal updated event: %v", err)
-		} else {
-			err = s.producer.WriteMessages(context.Background(),
-				kafka.Message{Value: eventBytes},
-			)
-			if err != nil {
-				log.Printf("failed to produce updated event: %v", err)
-			} else {
-				log.Println("Published ticket_updated event for ID:", id)
-			}
-		}
+func (s *ticketService) publishStatusChange(ticket *models.Ticket, oldStatus string) {
+	if oldStatus == ticket.Status {
+		return
+	}
+	event := models.TicketUpdatedEvent{
+		Type:      models.EventTicketUpdated,
+		EventID:   uuid.New().String(),
+		TicketID:  ticket.ID,
+		UserID:    ticket.UserID,
+		OldStatus: oldStatus,
+		NewStatus: ticket.Status,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to marshal updated event: %v", err)
+		return
 	}
+	err = s.producer.WriteMessages(context.Background(),
+		kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes},
+	)
+	if err != nil {
+		log.Printf("failed to produce updated event: %v", err)
+	} else {
+		log.Println("Published ticket_updated event for ID:", ticket.ID)
+	}
+}
 
-	return ticket, nil
+// publishAssignment announces that ticket was assigned to its agent by assignedBy
+func (s *ticketService) publishAssignment(ticket *models.Ticket, assignedBy uuid.UUID) {
+	event := models.TicketAssignedEvent{
+		Type:       models.EventTicketAssigned,
+		EventID:    uuid.New().String(),
+		TicketID:   ticket.ID,
+		UserID:     ticket.UserID,
+		AgentID:    *ticket.AgentID,
+		AssignedBy: assignedBy,
+		AssignedAt: time.Now().Format(time.RFC3339),
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to marshal assigned event: %v", err)
+		return
+	}
+	err = s.producer.WriteMessages(context.Background(),
+		kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes},
+	)
+	if err != nil {
+		log.Printf("failed to produce assigned event: %v", err)
+	} else {
+		log.Println("Published ticket_assigned event for ID:", ticket.ID)
+	}
+}
+
+func (s *ticketService) GetSummary(id uuid.UUID) (*models.TicketSummary, error) {
+	return s.repo.FindSummary(id)
+}
+
+func (s *ticketService) CommentAdded(ticket *models.Ticket, comment *models.Comment) {
+	event := models.TicketContentUpdatedEvent{
+		Type:        models.EventTicketContentUpdated,
+		EventID:     uuid.New().String(),
+		TicketID:    ticket.ID,
+		UserID:      ticket.UserID,
+		Title:       ticket.Title,
+		Description: ticket.Description,
+		CommentID:   &comment.ID,
+		AuthorID:    &comment.AuthorID,
+		AuthorRole:  comment.AuthorRole,
+		Internal:    !comment.Public,
+		UpdatedAt:   time.Now().Format(time.RFC3339),
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed to marshal content updated event: %v", err)
+		return
+	}
+	err = s.producer.WriteMessages(context.Background(),
+		kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes},
+	)
+	if err != nil {
+		log.Printf("failed to produce content updated event: %v", err)
+	} else {
+		log.Println("Published ticket_content_updated event for comment on ID:", ticket.ID)
+	}
 }
 
 func (s *ticketService) CustomerUpdate(id uuid.UUID, req *models.CustomerUpdateTicketRequest, userID uuid.UUID) (*models.Ticket, error) {
@@ -240,6 +420,8 @@ func (s *ticketService) CustomerUpdate(id uuid.UUID, req *models.CustomerUpdateT
 
 	// Publish event for AI service
 	event := models.TicketContentUpdatedEvent{
+		Type:        models.EventTicketContentUpdated,
+		EventID:     uuid.New().String(),
 		TicketID:    ticket.ID,
 		UserID:      ticket.UserID,
 		Title:       ticket.Title,
@@ -251,7 +433,7 @@ func (s *ticketService) CustomerUpdate(id uuid.UUID, req *models.CustomerUpdateT
 		log.Printf("failed to marshal content updated event: %v", err)
 	} else {
 		err = s.producer.WriteMessages(context.Background(),
-			kafka.Message{Value: eventBytes},
+			kafka.Message{Key: []byte(ticket.ID.String()), Value: eventBytes},
 		)
 		if err != nil {
 			log.Printf("failed to produce content updated event: %v", err)
@@ -267,3 +449,35 @@ func (s *ticketService) CustomerUpdate(id uuid.UUID, req *models.CustomerUpdateT
 
 	return ticket, nil
 }
+
+func (s *ticketService) ListSimilar(id uuid.UUID) ([]models.SimilarTicket, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSimilar(id)
+}
+
+// recordCorrection keeps the latest AI run in sync when an agent changes the
+// category or priority by hand, so the accuracy report sees the correction.
+// Pending runs are left to the review endpoint.
+func (s *ticketService) recordCorrection(ticket *models.Ticket, agentID uuid.UUID) {
+	run, err := s.classifications.Latest(ticket.ID)
+	if err != nil || run.Status == models.ClassificationPending {
+		return // No AI run to correct
+	}
+	feedback := correctionFeedback(run, ticket.Category, ticket.Priority, agentID)
+	if len(feedback) == 0 {
+		return
+	}
+	run.FinalCategory, run.FinalPriority = ticket.Category, ticket.Priority
+	if run.FinalSuggestion == "" && run.Status != models.ClassificationRejected {
+		run.FinalSuggestion = run.Suggestion
+	}
+	if err := s.classifications.Update(run); err != nil {
+		log.Printf("Failed to update classification %s: %v", run.ID, err)
+		return
+	}
+	if err := s.classifications.CreateFeedback(feedback); err != nil {
+		log.Printf("Failed to record feedback for classification %s: %v", run.ID, err)
+	}
+}
@@ -0,0 +1,137 @@
+package ticket
+
+import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/redis"
+	"ai-ticketing-backend/services/ticket/repository"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeTicketRepo holds one ticket and counts the updates to it
+type fakeTicketRepo struct {
+	repository.TicketRepository
+	ticket  models.Ticket
+	updates int
+}
+
+func (r *fakeTicketRepo) FindByID(id uuid.UUID) (*models.Ticket, error) {
+	if id != r.ticket.ID {
+		return nil, fmt.Errorf("ticket not found")
+	}
+	t := r.ticket
+	return &t, nil
+}
+
+func (r *fakeTicketRepo) Update(ticket *models.Ticket) error {
+	r.ticket = *ticket
+	r.updates++
+	return nil
+}
+
+// newTestTicketService has nowhere to send events and cache evictions;
+// those only get logged
+func newTestTicketService(repo repository.TicketRepository) *ticketService {
+	return &ticketService{
+		repo:     repo,
+		producer: &kafka.Writer{Addr: kafka.TCP("127.0.0.1:1"), Async: true},
+		cache:    redis.New("127.0.0.1:1"),
+	}
+}
+
+func TestReopen(t *testing.T) {
+	resolved, agent := time.Now().Add(-time.Hour), uuid.New()
+	tests := []struct {
+		name       string
+		status     string
+		autoSolved *time.Time
+		wantStatus string
+	}{
+		{"waiting for the customer", "pending_customer", &resolved, "open"},
+		{"auto-closed", "closed", &resolved, "open"},
+		{"already reopened", "in_progress", &resolved, "in_progress"},
+		{"closed by an agent", "closed", nil, "closed"},
+		{"waiting on an agent's question", "pending_customer", nil, "pending_customer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since := time.Now()
+			repo := &fakeTicketRepo{ticket: models.Ticket{ID: uuid.New(), Status: tt.status, AgentID: &agent, AutoResolvedAt: tt.autoSolved, PendingCustomerSince: &since}}
+			if err := newTestTicketService(repo).Reopen(repo.ticket.ID); err != nil {
+				t.Fatal(err)
+			}
+			if repo.ticket.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", repo.ticket.Status, tt.wantStatus)
+			}
+			if reopened := tt.wantStatus != tt.status; reopened != (repo.updates == 1) {
+				t.Errorf("%d updates, want reopened = %t", repo.updates, reopened)
+			} else if reopened && repo.ticket.PendingCustomerSince != nil {
+				t.Error("reopened ticket still waits for the customer")
+			}
+		})
+	}
+}
+
+func TestConfirmResolution(t *testing.T) {
+	owner, resolved := uuid.New(), time.Now().Add(-time.Hour)
+	tests := []struct {
+		name    string
+		status  string
+		userID  uuid.UUID
+		wantErr string
+	}{
+		{"owner confirms", "pending_customer", owner, ""},
+		{"someone else's ticket", "pending_customer", uuid.New(), "unauthorized"},
+		{"already reopened", "open", owner, "invalid status: ticket is open"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTicketRepo{ticket: models.Ticket{ID: uuid.New(), UserID: owner, Status: tt.status, AutoResolvedAt: &resolved}}
+			got, err := newTestTicketService(repo).ConfirmResolution(repo.ticket.ID, tt.userID)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ConfirmResolution error = %v, want %q", err, tt.wantErr)
+				}
+				if repo.updates != 0 {
+					t.Error("ticket was updated")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != "closed" || repo.ticket.Status != "closed" || repo.ticket.PendingCustomerSince != nil {
+				t.Errorf("ticket = %+v, want it closed", repo.ticket)
+			}
+		})
+	}
+}
+
+func TestAgentStatusChangeEndsAutoResolution(t *testing.T) {
+	resolved := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name         string
+		status       string
+		wantResolved bool
+	}{
+		{"status changed", "in_progress", false},
+		{"status kept", "pending_customer", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTicketRepo{ticket: models.Ticket{ID: uuid.New(), Status: "pending_customer", AutoResolvedAt: &resolved}}
+			s := newTestTicketService(repo)
+			if _, err := s.Update(repo.ticket.ID, &models.UpdateTicketRequest{Status: &tt.status}, uuid.New(), "agent"); err != nil {
+				t.Fatal(err)
+			}
+			if got := repo.ticket.AutoResolvedAt != nil; got != tt.wantResolved {
+				t.Errorf("still auto-resolved = %t, want %t", got, tt.wantResolved)
+			}
+		})
+	}
+}
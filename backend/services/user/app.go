@@ -1,50 +1,37 @@
 package user
 
 import (
+	"ai-ticketing-backend/internal/models"
+	"ai-ticketing-backend/internal/pkg/config"
 	"ai-ticketing-backend/internal/pkg/db"
 	"ai-ticketing-backend/services/user/repository"
-	"fmt"
-	"os"
-
-	"github.com/joho/godotenv"
+	"log"
 )
 
-func Setup() UserService {
-	// Load .env file (if exists)
-	_ = godotenv.Load() // Ignores errors if no .env
-
-	// Load from env with fallbacks (using YOUR var names)
-	host := os.Getenv("DB_HOST")
-	if host == "" {
-		host = "postgres" // Matches your .env
-	}
-	username := os.Getenv("DB_USERNAME")
-	if username == "" {
-		username = "ticket_user"
-	}
-	pass := os.Getenv("DB_PASSWORD")
-	if pass == "" {
-		pass = "ticket123" // Matches your .env!
-	}
-	dbname := os.Getenv("DB_DATABASE")
-	if dbname == "" {
-		dbname = "Ticket"
-	}
-	port := os.Getenv("DB_PORT")
-	if port == "" {
-		port = "5432"
-	}
-
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=UTC",
-		host, username, pass, dbname, port)
-
-	dbConn, err := db.New(dsn)
+func Setup(cfg *config.Config) UserService {
+	dbConn, err := db.New(cfg.DB.DSN())
 	if err != nil {
 		panic(err)
 	}
 
 	repo := repository.NewUserRepository(dbConn)
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, cfg.JWT.Secret)
+	if cfg.Users.AdminEmail != "" {
+		seedAdmin(repo, svc, cfg.Users)
+	}
 
 	return svc
 }
+
+// seedAdmin creates the configured admin unless the email is already taken
+func seedAdmin(repo repository.UserRepository, svc UserService, cfg config.UsersConfig) {
+	if _, err := repo.FindByEmail(cfg.AdminEmail); err == nil {
+		return
+	}
+	_, err := svc.CreateUser(&models.CreateUserRequest{Email: cfg.AdminEmail, Password: cfg.AdminPassword, Role: models.RoleAdmin})
+	if err != nil {
+		log.Printf("Failed to seed admin %s: %v", cfg.AdminEmail, err)
+		return
+	}
+	log.Printf("Seeded admin %s", cfg.AdminEmail)
+}
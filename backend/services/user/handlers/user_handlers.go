@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"ai-ticketing-backend/internal/models"
 	"ai-ticketing-backend/services/user"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UserHandlers struct {
@@ -34,6 +37,51 @@ func (h *UserHandlers) Register(c *gin.Context) {
 	c.JSON(http.StatusCreated, user)
 }
 
+// CreateUser for POST /api/v1/users (admins only)
+func (h *UserHandlers) CreateUser(c *gin.Context) {
+	var req models.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.svc.CreateUser(&req)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	user.Password = "" // Hide
+	c.JSON(http.StatusCreated, user)
+}
+
+// UpdateUser for PATCH /api/v1/users/:id (admins only)
+func (h *UserHandlers) UpdateUser(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	var req models.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.svc.UpdateUser(id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		case strings.Contains(err.Error(), "invalid"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
 // LoginHandler for POST /api/v1/users/login
 func (h *UserHandlers) Login(c *gin.Context) {
 	var req models.LoginRequest
@@ -7,6 +7,8 @@ import (
 
 type UserService interface {
 	Register(req *models.RegisterRequest) (*models.User, error)
+	CreateUser(req *models.CreateUserRequest) (*models.User, error)
+	UpdateUser(id uuid.UUID, req *models.UpdateUserRequest) (*models.User, error)
 	Login(req *models.LoginRequest) (string, *models.User, error)
 	GetUser(id uuid.UUID) (*models.User, error)
 	ListUsers() ([]models.User, error)
@@ -52,3 +52,16 @@ func AuthMiddleware(svc service.UserService) gin.HandlerFunc {
 		}
 	}
 }
+
+// AdminAuthMiddleware checks for 'admin' role (user management)
+func AdminAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists || role.(string) != models.RoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Admin role required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
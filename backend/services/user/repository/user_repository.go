@@ -9,6 +9,7 @@ import (
 
 type UserRepository interface {
 	Create(user *models.User) error
+	Update(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id uuid.UUID) (*models.User, error)
 	List() ([]models.User, error) // For agents to list users
@@ -26,6 +27,10 @@ func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
 
+func (r *userRepository) Update(user *models.User) error {
+	return r.db.Model(user).Updates(map[string]interface{}{"role": user.Role, "tenant": user.Tenant}).Error
+}
+
 func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	var user models.User
 	err := r.db.Where("email = ?", email).First(&user).Error
@@ -5,7 +5,6 @@ import (
 	"ai-ticketing-backend/services/user/repository"
 	"errors"
 	"log"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,15 +14,11 @@ import (
 
 type userService struct {
 	repo      repository.UserRepository
-	jwtSecret string // Env var in prod
+	jwtSecret string // From config.JWT
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	secret := os.Getenv("JWT_SECRET") // Load from env (fallback if unset)
-	if secret == "" {
-		secret = "super-secret-key-change-me" // Fallback for local
-	}
-	return &userService{repo: repo, jwtSecret: secret}
+func NewUserService(repo repository.UserRepository, jwtSecret string) UserService {
+	return &userService{repo: repo, jwtSecret: jwtSecret}
 }
 
 func (s *userService) GetJWTSecret() string {
@@ -32,25 +27,61 @@ func (s *userService) GetJWTSecret() string {
 
 
 
+// Register signs up a customer; agents and admins are created by an admin
 func (s *userService) Register(req *models.RegisterRequest) (*models.User, error) {
 	log.Printf("Attempting to register user with email: %s", req.Email)
+	return s.create(req.Email, req.Password, models.RoleCustomer, models.DefaultTenant)
+}
+
+// CreateUser adds a user with any role (admins only)
+func (s *userService) CreateUser(req *models.CreateUserRequest) (*models.User, error) {
+	log.Printf("Creating %s user with email: %s", req.Role, req.Email)
+	tenant := req.Tenant
+	if tenant == "" {
+		tenant = models.DefaultTenant
+	}
+	return s.create(req.Email, req.Password, req.Role, tenant)
+}
+
+// UpdateUser changes a user's role or tenant (admins only)
+func (s *userService) UpdateUser(id uuid.UUID, req *models.UpdateUserRequest) (*models.User, error) {
+	user, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Role != "" {
+		user.Role = req.Role
+	}
+	if req.Tenant != "" {
+		user.Tenant = req.Tenant
+	}
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+	log.Printf("Updated user %s: role=%s tenant=%s", user.ID, user.Role, user.Tenant)
+	user.Password = ""
+	return user, nil
+}
+
+func (s *userService) create(email, password, role, tenant string) (*models.User, error) {
 	// Check if email exists
-	if _, err := s.repo.FindByEmail(req.Email); err == nil {
-		log.Printf("Registration failed: email %s already registered", req.Email)
+	if _, err := s.repo.FindByEmail(email); err == nil {
+		log.Printf("Registration failed: email %s already registered", email)
 		return nil, errors.New("email already registered")
 	}
 
 	// Hash password
-	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
 
 	user := &models.User{
 		ID:       uuid.New(),
-		Email:    req.Email,
+		Email:    email,
 		Password: string(hashed),
-		Role:     req.Role,
+		Role:     role,
+		Tenant:   tenant,
 	}
 
 	if err := s.repo.Create(user); err != nil {
@@ -77,6 +108,7 @@ func (s *userService) Login(req *models.LoginRequest) (string, *models.User, err
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
 		"role":    user.Role,
+		"tenant":  user.Tenant,
 		"exp":     time.Now().Add(time.Hour * 24).Unix(), // Expires in 24h
 	})
 